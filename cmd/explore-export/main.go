@@ -0,0 +1,129 @@
+// Command explore-export writes a snapshot of the decisions table to a file
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/export"
+)
+
+const usage = `usage: explore-export [flags] -o FILE
+
+Exports decisions from the database at DATABASE_URL to FILE, and writes a
+manifest with the row count and SHA-256 checksum of FILE next to it.
+
+flags:
+`
+
+func main() {
+	fs := flag.NewFlagSet("explore-export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", "", "file to write, replaced atomically once the export completes")
+	format := fs.String("format", "jsonl", "output format, one of "+strings.Join(export.Formats, ", "))
+	compress := fs.Bool("gzip", false, "gzip JSON lines and CSV output, or use gzip column compression for Parquet")
+	since := fs.String("since", "", "only decisions last changed at or after this RFC 3339 time or date")
+	until := fs.String("until", "", "only decisions last changed before this RFC 3339 time or date")
+	user := fs.String("user", "", "only decisions made by or about this user")
+	manifestPath := fs.String("manifest", "", "where to write the manifest, defaults to FILE.manifest.json")
+	fs.Parse(os.Args[1:])
+
+	if *output == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *manifestPath == "" {
+		*manifestPath = *output + ".manifest.json"
+	}
+
+	filter := export.Filter{UserID: *user}
+	var err error
+	if filter.Since, err = parseTime(*since); err != nil {
+		log.Fatalf("invalid -since: %v", err)
+	}
+	if filter.Until, err = parseTime(*until); err != nil {
+		log.Fatalf("invalid -until: %v", err)
+	}
+
+	if err := run(filter, *output, *manifestPath, *format, *compress); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(filter export.Filter, output, manifestPath, format string, compress bool) error {
+	cfg, err := config.Load(nil, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	// Write next to the destination and rename, so readers never see a partial export
+	tmp, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	manifest, err := export.Export(ctx, export.NewPostgresSource(pool), filter, tmp, format, compress)
+	if err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), output); err != nil {
+		return err
+	}
+
+	manifest.File = filepath.Base(output)
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(manifestPath, append(raw, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Printf("exported %d decisions to %s (sha256 %s)\n", manifest.Rows, output, manifest.SHA256)
+	return nil
+}
+
+// parseTime parses an RFC 3339 time or a date, returning zero time if value is empty
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("expected an RFC 3339 time or YYYY-MM-DD date")
+	}
+	return t, nil
+}
@@ -0,0 +1,144 @@
+// Command explore-import bulk loads historical decisions from CSV or JSON lines
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/importer"
+	"muzz-explore-service/internal/logging"
+)
+
+const usage = `usage: explore-import [flags] FILE
+
+Imports decisions from FILE, or standard input if FILE is -, into the database
+at DATABASE_URL. Rerunning an interrupted import with the same name and input
+resumes after the last committed batch.
+
+flags:
+`
+
+func main() {
+	fs := flag.NewFlagSet("explore-import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "", "csv or jsonl, detected from the file extension if unset")
+	name := fs.String("name", "", "checkpoint name identifying the import, defaults to the file name")
+	batchSize := fs.Int("batch-size", importer.DefaultBatchSize, "records merged per transaction")
+	dryRun := fs.Bool("dry-run", false, "validate the input and report invalid records without writing anything")
+	skipInvalid := fs.Bool("skip-invalid", false, "log and skip invalid records instead of stopping")
+	fs.Parse(os.Args[1:])
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err := run(fs.Arg(0), *format, *name, *batchSize, *dryRun, *skipInvalid); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(path, format, name string, batchSize int, dryRun, skipInvalid bool) error {
+	cfg, err := config.Load(nil, nil)
+	if err != nil {
+		return err
+	}
+	logger, err := logging.New(os.Stderr, cfg)
+	if err != nil {
+		return err
+	}
+
+	input := os.Stdin
+	if path != "-" {
+		if input, err = os.Open(path); err != nil {
+			return err
+		}
+		defer input.Close()
+	}
+
+	if format == "" {
+		if format, err = detectFormat(path); err != nil {
+			return err
+		}
+	}
+	reader, err := importer.NewReader(input, format)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		summary, err := importer.Check(reader, func(err *importer.RecordError) {
+			fmt.Fprintln(os.Stderr, err)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d records, %d invalid\n", summary.Records, summary.Invalid)
+		if summary.Invalid > 0 {
+			return errors.New("input has invalid records")
+		}
+		return nil
+	}
+
+	if name == "" {
+		if path == "-" {
+			return errors.New("-name is required when importing from standard input")
+		}
+		name = filepath.Base(path)
+	}
+
+	if err := checkSchema(cfg); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	summary, err := importer.NewImporter(importer.NewPostgresStore(pool), batchSize, skipInvalid, logger).Run(ctx, name, reader)
+	fmt.Printf("%d records: %d inserted, %d updated, %d stale, %d invalid, %d already imported\n",
+		summary.Records, summary.Inserted, summary.Updated, summary.Stale, summary.Invalid, summary.Resumed)
+	return err
+}
+
+// detectFormat picks the input format from the file extension
+func detectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv", nil
+	case ".jsonl", ".ndjson":
+		return "jsonl", nil
+	default:
+		return "", fmt.Errorf("can't detect the format of %s, set -format", path)
+	}
+}
+
+// checkSchema refuses to import into a database that isn't fully migrated
+func checkSchema(cfg *config.Config) error {
+	migrator, err := db.NewMigrator(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.CheckVersion()
+}
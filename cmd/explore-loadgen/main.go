@@ -0,0 +1,99 @@
+// Command explore-loadgen simulates swipe traffic against a running ExploreService
+// and reports latency percentiles and error rates per RPC
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"muzz-explore-service/internal/loadgen"
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "server address")
+	token := flag.String("token", os.Getenv("EXPLORE_TOKEN"), "bearer token for every call, needs the internal role when authentication is enabled")
+	useTLS := flag.Bool("tls", false, "connect over TLS")
+	skipVerify := flag.Bool("insecure-skip-verify", false, "don't verify the server certificate")
+	users := flag.Int("users", 10000, "simulated user population")
+	skew := flag.Float64("zipf", 1.1, "Zipf exponent of user popularity, greater than 1; higher concentrates likes on fewer users")
+	likeRatio := flag.Float64("like-ratio", 0.4, "fraction of decisions that are likes")
+	mixFlag := flag.String("mix", loadgen.DefaultMix.String(), "weights of each call: put (PutDecision), list (ListLikedYou), new (ListNewLikedYou) and count (CountLikedYou)")
+	concurrency := flag.Int("concurrency", 16, "calls in flight at once")
+	rate := flag.Float64("rate", 0, "target calls per second, 0 for as fast as possible")
+	duration := flag.Duration("duration", 30*time.Second, "how long to generate load")
+	seed := flag.Int64("seed", 0, "random seed, defaults to the current time")
+	flag.Parse()
+
+	mix, err := loadgen.ParseMix(*mixFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	transport := insecure.NewCredentials()
+	if *useTLS || *skipVerify {
+		transport = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: *skipVerify})
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transport)}
+	if *token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken{token: *token, secure: *useTLS || *skipVerify}))
+	}
+	conn, err := grpc.NewClient(*addr, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Stop early on interrupt, still reporting what ran
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	fmt.Fprintf(os.Stderr, "generating load against %s for %s: %d users, zipf %.2f, like ratio %.2f, mix %s, concurrency %d, seed %d\n",
+		*addr, *duration, *users, *skew, *likeRatio, mix, *concurrency, *seed)
+
+	report, err := loadgen.Run(ctx, pb.NewExploreServiceClient(conn), loadgen.Config{
+		Users:       *users,
+		Skew:        *skew,
+		LikeRatio:   *likeRatio,
+		Mix:         mix,
+		Concurrency: *concurrency,
+		Rate:        *rate,
+		Seed:        *seed,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := report.Write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// bearerToken sends a token in the authorization metadata of each call
+type bearerToken struct {
+	token  string
+	secure bool
+}
+
+func (b bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + b.token}, nil
+}
+
+func (b bearerToken) RequireTransportSecurity() bool {
+	return b.secure
+}
@@ -0,0 +1,205 @@
+// Command explore-seed fills a local database with a reproducible graph of
+// synthetic users and decisions
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/export"
+	"muzz-explore-service/internal/importer"
+	"muzz-explore-service/internal/seed"
+)
+
+const usage = `usage: explore-seed [flags]
+
+Generates users and decisions from a seed and loads them into the database at
+DATABASE_URL, or writes them to a file in the explore-import format with -o.
+The same flags always generate the same decisions.
+
+flags:
+`
+
+func main() {
+	defaults := seed.DefaultConfig()
+
+	fs := flag.NewFlagSet("explore-seed", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	users := fs.Int("users", defaults.Users, "number of users, named "+seed.UserPrefix+"N")
+	decisions := fs.Int("decisions", defaults.Decisions, "decisions each user makes about random other users")
+	popularLikers := fs.Int("popular-likers", defaults.PopularLikers, "users who like "+seed.User(0)+", so its likers span several pages")
+	likeRatio := fs.Float64("like-ratio", defaults.LikeRatio, "fraction of decisions that are likes")
+	superLikeRatio := fs.Float64("super-like-ratio", defaults.SuperLikeRatio, "fraction of likes that are super-likes")
+	mutualRatio := fs.Float64("mutual-ratio", defaults.MutualRatio, "fraction of likes the recipient likes back")
+	flipRatio := fs.Float64("flip-ratio", defaults.FlipRatio, "fraction of decisions later changed from like to pass or back")
+	collisionRatio := fs.Float64("collision-ratio", defaults.CollisionRatio, "fraction of likes made at the same time as the recipient's previous like")
+	start := fs.String("start", defaults.Start.Format(time.DateOnly), "RFC 3339 time or date of the first decisions")
+	span := fs.Duration("span", defaults.Span, "how long after -start decisions keep being made")
+	seedFlag := fs.Int64("seed", defaults.Seed, "random seed")
+	output := fs.String("o", "", "write the decisions to this file, or - for standard output, instead of the database")
+	format := fs.String("format", "", "csv or jsonl output, detected from the -o file extension if unset")
+	reset := fs.Bool("reset", false, "delete every row about "+seed.UserPrefix+"* users before loading")
+	batchSize := fs.Int("batch-size", importer.DefaultBatchSize, "records merged per transaction")
+	fs.Parse(os.Args[1:])
+
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg := seed.Config{
+		Users:          *users,
+		Decisions:      *decisions,
+		PopularLikers:  *popularLikers,
+		LikeRatio:      *likeRatio,
+		SuperLikeRatio: *superLikeRatio,
+		MutualRatio:    *mutualRatio,
+		FlipRatio:      *flipRatio,
+		CollisionRatio: *collisionRatio,
+		Span:           *span,
+		Seed:           *seedFlag,
+	}
+	var err error
+	if cfg.Start, err = parseTime(*start); err != nil {
+		log.Fatalf("invalid -start: %v", err)
+	}
+
+	dataset, err := seed.Generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	stats := dataset.Stats
+	fmt.Fprintf(os.Stderr, "generated %d decisions between %d users: %d likes (%d super), %d mutual pairs, %d flips, %d timestamp collisions; %s has %d likers, %d new\n",
+		stats.Decisions, cfg.Users, stats.Likes, stats.SuperLikes, stats.Mutual, stats.Flips, stats.Collisions,
+		seed.User(0), len(dataset.Likers(seed.User(0), false)), len(dataset.Likers(seed.User(0), true)))
+
+	if *output != "" {
+		err = write(dataset, *output, *format)
+	} else {
+		err = load(dataset, *reset, *batchSize)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// write encodes the records in the format explore-import reads
+func write(dataset *seed.Dataset, path, format string) error {
+	if format == "" {
+		format = "jsonl"
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			format = "csv"
+		}
+	}
+	if format != "jsonl" && format != "csv" {
+		return fmt.Errorf("unsupported format %q, expected csv or jsonl", format)
+	}
+
+	out := os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	w, err := export.NewWriter(out, format, false)
+	if err != nil {
+		return err
+	}
+	for i, record := range dataset.Records {
+		err := w.Write(export.Row{
+			ID:              int64(i + 1),
+			ActorUserID:     record.ActorUserID,
+			RecipientUserID: record.RecipientUserID,
+			Liked:           record.Liked,
+			SuperLike:       record.SuperLike,
+			CreatedAt:       record.CreatedAt,
+			UpdatedAt:       record.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		return out.Close()
+	}
+	return nil
+}
+
+// load merges the records into the database, after deleting earlier seeded data if reset is set
+func load(dataset *seed.Dataset, reset bool, batchSize int) error {
+	cfg, err := config.Load(nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := checkSchema(cfg); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if reset {
+		deleted, err := seed.Reset(ctx, pool)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "deleted %d seeded decisions\n", deleted)
+	}
+
+	result, err := dataset.Load(ctx, importer.NewPostgresStore(pool), "seed", batchSize)
+	fmt.Printf("%d records: %d inserted, %d updated, %d stale\n",
+		len(dataset.Records), result.Inserted, result.Updated, result.Stale)
+	return err
+}
+
+// checkSchema refuses to seed a database that isn't fully migrated
+func checkSchema(cfg *config.Config) error {
+	migrator, err := db.NewMigrator(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.CheckVersion()
+}
+
+// parseTime parses an RFC 3339 time or a date
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("expected an RFC 3339 time or YYYY-MM-DD date")
+	}
+	return t, nil
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+// execFunc runs a command against the service with its positional arguments
+type execFunc func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error
+
+type command struct {
+	name    string
+	args    []string
+	usage   string
+	summary string
+	// setup registers the command's flags and returns the function running it
+	setup func(fs *flag.FlagSet) execFunc
+}
+
+var commands = map[string]command{}
+
+func register(cmd command) {
+	commands[cmd.name] = cmd
+}
+
+func init() {
+	register(command{
+		name:    "decide",
+		args:    []string{"ACTOR", "RECIPIENT"},
+		usage:   "[-pass] [-super] ACTOR RECIPIENT",
+		summary: "Record that ACTOR liked (or passed on) RECIPIENT",
+		setup:   setupDecide,
+	})
+	register(command{
+		name:    "likers",
+		args:    []string{"RECIPIENT"},
+		usage:   "[-new] [-all] [-sort newest|oldest] [-type any|regular|super] [-since TIME] [-until TIME] [-page-token TOKEN] RECIPIENT",
+		summary: "List the users who liked RECIPIENT",
+		setup:   setupLikers,
+	})
+	register(command{
+		name:    "count",
+		args:    []string{"RECIPIENT"},
+		usage:   "[-unseen] RECIPIENT",
+		summary: "Count the users who liked RECIPIENT",
+		setup:   setupCount,
+	})
+	register(command{
+		name:    "relationship",
+		args:    []string{"USER", "OTHER"},
+		usage:   "USER OTHER",
+		summary: "Show whether two users have liked each other",
+		setup:   setupRelationship,
+	})
+	register(command{
+		name:    "quota",
+		args:    []string{"USER"},
+		usage:   "USER",
+		summary: "Show how many likes USER has left",
+		setup:   setupQuota,
+	})
+	register(command{
+		name:    "score",
+		args:    []string{"USER"},
+		usage:   "USER",
+		summary: "Show the desirability score of USER",
+		setup:   setupScore,
+	})
+	register(command{
+		name:    "deck",
+		args:    []string{"ACTOR"},
+		usage:   "[-limit N] [-page-token TOKEN] ACTOR",
+		summary: "Show the next candidates in ACTOR's explore deck",
+		setup:   setupDeck,
+	})
+	register(command{
+		name:    "seen",
+		args:    []string{"RECIPIENT"},
+		usage:   "[-until TIME] RECIPIENT",
+		summary: "Mark RECIPIENT's likes as seen",
+		setup:   setupSeen,
+	})
+}
+
+func setupDecide(fs *flag.FlagSet) execFunc {
+	pass := fs.Bool("pass", false, "record a pass instead of a like")
+	super := fs.Bool("super", false, "record a super-like")
+
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		if *pass && *super {
+			return errors.New("-pass and -super can't be combined")
+		}
+		resp, err := client.PutDecision(ctx, &pb.PutDecisionRequest{
+			ActorUserId:     args[0],
+			RecipientUserId: args[1],
+			LikedRecipient:  !*pass,
+			SuperLike:       *super,
+		})
+		if err != nil {
+			return err
+		}
+		return p.print(resp, []string{"MUTUAL"}, [][]string{{formatBool(resp.MutualLikes)}})
+	}
+}
+
+func setupLikers(fs *flag.FlagSet) execFunc {
+	newOnly := fs.Bool("new", false, "leave out users the recipient has liked back")
+	all := fs.Bool("all", false, "follow pagination tokens and list every page")
+	sortOrder := fs.String("sort", "newest", "newest or oldest first")
+	likeType := fs.String("type", "any", "any, regular or super likes")
+	since := fs.String("since", "", "only likes made at or after this RFC 3339 time or Unix timestamp")
+	until := fs.String("until", "", "only likes made before this RFC 3339 time or Unix timestamp")
+	pageToken := fs.String("page-token", "", "pagination token from a previous page")
+
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		req := &pb.ListLikedYouRequest{RecipientUserId: args[0]}
+
+		switch *sortOrder {
+		case "newest":
+			req.SortOrder = pb.SortOrder_SORT_ORDER_NEWEST_FIRST
+		case "oldest":
+			req.SortOrder = pb.SortOrder_SORT_ORDER_OLDEST_FIRST
+		default:
+			return fmt.Errorf("unknown sort order %q, expected newest or oldest", *sortOrder)
+		}
+		switch *likeType {
+		case "any":
+			req.LikeType = pb.LikeType_LIKE_TYPE_ANY
+		case "regular":
+			req.LikeType = pb.LikeType_LIKE_TYPE_REGULAR
+		case "super":
+			req.LikeType = pb.LikeType_LIKE_TYPE_SUPER
+		default:
+			return fmt.Errorf("unknown like type %q, expected any, regular or super", *likeType)
+		}
+		var err error
+		if req.SinceUnixTimestamp, err = parseTimeFlag("since", *since); err != nil {
+			return err
+		}
+		if req.UntilUnixTimestamp, err = parseTimeFlag("until", *until); err != nil {
+			return err
+		}
+		if *pageToken != "" {
+			req.PaginationToken = pageToken
+		}
+
+		list := client.ListLikedYou
+		if *newOnly {
+			list = client.ListNewLikedYou
+		}
+
+		resp, err := list(ctx, req)
+		if err != nil {
+			return err
+		}
+		// The last page has an empty token
+		for *all && resp.GetNextPaginationToken() != "" {
+			req.PaginationToken = resp.NextPaginationToken
+			page, err := list(ctx, req)
+			if err != nil {
+				return err
+			}
+			page.Likers = append(resp.Likers, page.Likers...)
+			resp = page
+		}
+
+		rows := make([][]string, 0, len(resp.Likers))
+		for _, liker := range resp.Likers {
+			id := liker.ActorId
+			if id == "" {
+				id = liker.Handle
+			}
+			rows = append(rows, []string{id, formatTime(liker.UnixTimestamp), formatBool(liker.SuperLike), formatBool(liker.Seen)})
+		}
+		if err := p.print(resp, []string{"LIKER", "LIKED AT", "SUPER", "SEEN"}, rows); err != nil {
+			return err
+		}
+
+		if !p.json {
+			if resp.Redacted {
+				p.note("redacted: the recipient isn't entitled to see who liked them, %d likers in total", resp.TotalCount)
+			}
+			if resp.GetNextPaginationToken() != "" {
+				p.note("more likers available, pass -page-token %s or -all", resp.GetNextPaginationToken())
+			}
+		}
+		return nil
+	}
+}
+
+func setupCount(fs *flag.FlagSet) execFunc {
+	unseen := fs.Bool("unseen", false, "only count likes the recipient hasn't seen")
+
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		req := &pb.CountLikedYouRequest{RecipientUserId: args[0]}
+
+		count := client.CountLikedYou
+		if *unseen {
+			count = client.CountUnseenLikedYou
+		}
+		resp, err := count(ctx, req)
+		if err != nil {
+			return err
+		}
+		return p.print(resp, []string{"COUNT"}, [][]string{{strconv.FormatUint(resp.Count, 10)}})
+	}
+}
+
+func setupRelationship(fs *flag.FlagSet) execFunc {
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		user, other := args[0], args[1]
+		liked, err := findLike(ctx, client, user, other)
+		if err != nil {
+			return err
+		}
+		likedBack, err := findLike(ctx, client, other, user)
+		if err != nil {
+			return err
+		}
+
+		result, err := structpb.NewStruct(map[string]any{
+			"likes":        []any{likeJSON(user, other, liked), likeJSON(other, user, likedBack)},
+			"mutual_likes": liked != nil && likedBack != nil,
+		})
+		if err != nil {
+			return err
+		}
+		return p.print(result, []string{"USER", "ABOUT", "LIKED", "LIKED AT", "SUPER"}, [][]string{
+			likeRow(user, other, liked),
+			likeRow(other, user, likedBack),
+		})
+	}
+}
+
+// findLike pages through the recipient's likers for the actor's like,
+// returning nil if the actor hasn't liked the recipient
+func findLike(ctx context.Context, client pb.ExploreServiceClient, actor, recipient string) (*pb.ListLikedYouResponse_Liker, error) {
+	req := &pb.ListLikedYouRequest{RecipientUserId: recipient}
+	for {
+		resp, err := client.ListLikedYou(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Redacted {
+			return nil, fmt.Errorf("can't tell who liked %s, as the recipient isn't entitled to see their likers", recipient)
+		}
+		for _, liker := range resp.Likers {
+			if liker.ActorId == actor {
+				return liker, nil
+			}
+		}
+		// The last page has an empty token
+		if resp.GetNextPaginationToken() == "" {
+			return nil, nil
+		}
+		req.PaginationToken = resp.NextPaginationToken
+	}
+}
+
+func likeRow(actor, recipient string, like *pb.ListLikedYouResponse_Liker) []string {
+	if like == nil {
+		return []string{actor, recipient, "no", "-", "-"}
+	}
+	return []string{actor, recipient, "yes", formatTime(like.UnixTimestamp), formatBool(like.SuperLike)}
+}
+
+func likeJSON(actor, recipient string, like *pb.ListLikedYouResponse_Liker) map[string]any {
+	result := map[string]any{"actor_id": actor, "recipient_id": recipient, "liked": like != nil}
+	if like != nil {
+		result["unix_timestamp"] = float64(like.UnixTimestamp)
+		result["super_like"] = like.SuperLike
+	}
+	return result
+}
+
+func setupQuota(fs *flag.FlagSet) execFunc {
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		resp, err := client.GetQuota(ctx, &pb.GetQuotaRequest{UserId: args[0]})
+		if err != nil {
+			return err
+		}
+		if resp.Unlimited {
+			return p.print(resp, []string{"LIMIT", "REMAINING", "RESETS AT"}, [][]string{{"unlimited", "-", "-"}})
+		}
+		return p.print(resp, []string{"LIMIT", "REMAINING", "RESETS AT"}, [][]string{{
+			strconv.FormatUint(uint64(resp.Limit), 10),
+			strconv.FormatUint(uint64(resp.Remaining), 10),
+			formatTime(resp.ResetUnixTimestamp),
+		}})
+	}
+}
+
+func setupScore(fs *flag.FlagSet) execFunc {
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		resp, err := client.GetUserScore(ctx, &pb.GetUserScoreRequest{UserId: args[0]})
+		if err != nil {
+			return err
+		}
+		return p.print(resp, []string{"SCORE", "LIKES", "PASSES", "UPDATED AT"}, [][]string{{
+			strconv.FormatFloat(resp.Score, 'f', 1, 64),
+			strconv.FormatUint(resp.LikesReceived, 10),
+			strconv.FormatUint(resp.PassesReceived, 10),
+			formatTime(resp.UpdatedUnixTimestamp),
+		}})
+	}
+}
+
+func setupDeck(fs *flag.FlagSet) execFunc {
+	limit := fs.Uint("limit", 0, "candidates to return, the server default if 0")
+	pageToken := fs.String("page-token", "", "pagination token from a previous page")
+
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		req := &pb.GetExploreDeckRequest{ActorUserId: args[0], Limit: uint32(*limit)}
+		if *pageToken != "" {
+			req.PaginationToken = pageToken
+		}
+
+		resp, err := client.GetExploreDeck(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(resp.Candidates))
+		for _, candidate := range resp.Candidates {
+			rows = append(rows, []string{candidate.UserId})
+		}
+		if err := p.print(resp, []string{"CANDIDATE"}, rows); err != nil {
+			return err
+		}
+		if !p.json && resp.GetNextPaginationToken() != "" {
+			p.note("more candidates available, pass -page-token %s", resp.GetNextPaginationToken())
+		}
+		return nil
+	}
+}
+
+func setupSeen(fs *flag.FlagSet) execFunc {
+	until := fs.String("until", "", "mark likes made up to this RFC 3339 time or Unix timestamp, defaults to now")
+
+	return func(ctx context.Context, client pb.ExploreServiceClient, p printer, args []string) error {
+		req := &pb.MarkLikesSeenRequest{RecipientUserId: args[0]}
+		var err error
+		if req.SeenUntilUnixTimestamp, err = parseTimeFlag("until", *until); err != nil {
+			return err
+		}
+
+		resp, err := client.MarkLikesSeen(ctx, req)
+		if err != nil {
+			return err
+		}
+		return p.print(resp, []string{"SEEN BEFORE"}, [][]string{{formatTime(resp.SeenBeforeUnixTimestamp)}})
+	}
+}
+
+// parseTimeFlag parses an RFC 3339 time or Unix timestamp, returning nil if value is empty
+func parseTimeFlag(name, value string) (*uint64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if unix, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return &unix, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -%s %q, expected an RFC 3339 time or Unix timestamp", name, value)
+	}
+	unix := uint64(t.Unix())
+	return &unix, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// connectionFlags select the server and how to authenticate to it
+type connectionFlags struct {
+	addr       string
+	token      string
+	tokenFile  string
+	tls        bool
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+	skipVerify bool
+}
+
+func (c *connectionFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.addr, "addr", envOr("EXPLORE_ADDR", "localhost:8080"), "server address")
+	fs.StringVar(&c.token, "token", os.Getenv("EXPLORE_TOKEN"), "bearer token sent with each call")
+	fs.StringVar(&c.tokenFile, "token-file", "", "read the bearer token from a file")
+	fs.BoolVar(&c.tls, "tls", false, "connect over TLS, implied by the other TLS flags")
+	fs.StringVar(&c.caFile, "ca-file", "", "CA bundle to verify the server with, instead of the system roots")
+	fs.StringVar(&c.certFile, "cert-file", "", "client certificate for mutual TLS")
+	fs.StringVar(&c.keyFile, "key-file", "", "client private key for mutual TLS")
+	fs.StringVar(&c.serverName, "server-name", "", "expected server name, if it differs from the address")
+	fs.BoolVar(&c.skipVerify, "insecure-skip-verify", false, "don't verify the server certificate")
+}
+
+// dial connects to the server with the configured transport and credentials
+func (c *connectionFlags) dial() (*grpc.ClientConn, error) {
+	secure := c.tls || c.caFile != "" || c.certFile != "" || c.serverName != "" || c.skipVerify
+
+	transport := insecure.NewCredentials()
+	if secure {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport = credentials.NewTLS(tlsConfig)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transport)}
+
+	token := c.token
+	if c.tokenFile != "" {
+		raw, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token: %w", err)
+		}
+		token = strings.TrimSpace(string(raw))
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken{token: token, secure: secure}))
+	}
+
+	return grpc.NewClient(c.addr, opts...)
+}
+
+func (c *connectionFlags) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.serverName,
+		InsecureSkipVerify: c.skipVerify,
+	}
+
+	if c.caFile != "" {
+		pem, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.caFile)
+		}
+	}
+
+	if c.certFile != "" || c.keyFile != "" {
+		if c.certFile == "" || c.keyFile == "" {
+			return nil, errors.New("-cert-file and -key-file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// bearerToken sends a token in the authorization metadata of each call
+type bearerToken struct {
+	token  string
+	secure bool
+}
+
+func (b bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + b.token}, nil
+}
+
+// RequireTransportSecurity allows tokens over plaintext only when TLS is off,
+// for local development against servers without TLS
+func (b bearerToken) RequireTransportSecurity() bool {
+	return b.secure
+}
+
+// formatError renders gRPC errors as "CODE: message"
+func formatError(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return fmt.Sprintf("%s: %s", st.Code(), st.Message())
+	}
+	return err.Error()
+}
+
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,115 @@
+// Command explorectl calls the ExploreService from the command line
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("explorectl "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: explorectl %s %s\n\n%s\n\nflags:\n", cmd.name, cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	var conn connectionFlags
+	conn.register(fs)
+	format := fs.String("output", "table", "output format, table or json")
+	timeout := fs.Duration("timeout", 30*time.Second, "deadline for the whole command")
+	exec := cmd.setup(fs)
+
+	positional, err := parseInterleaved(fs, args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(positional) != len(cmd.args) {
+		fmt.Fprintf(stderr, "explorectl %s expects %d argument(s): %s\n", cmd.name, len(cmd.args), strings.Join(cmd.args, " "))
+		return 2
+	}
+	p, err := newPrinter(stdout, stderr, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cc, err := conn.dial()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer cc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := exec(ctx, pb.NewExploreServiceClient(cc), p, positional); err != nil {
+		fmt.Fprintln(stderr, formatError(err))
+		return 1
+	}
+	return 0
+}
+
+// parseInterleaved parses flags that may appear before, between or after positional arguments
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: explorectl <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-13s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `explorectl <command> -h` for the flags of a command.")
+	fmt.Fprintln(w, "EXPLORE_ADDR and EXPLORE_TOKEN set the default server address and bearer token.")
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+// fakeServer serves three pages of likers and records the last authorization header
+type fakeServer struct {
+	pb.UnimplementedExploreServiceServer
+	authorization string
+}
+
+func (f *fakeServer) ListLikedYou(ctx context.Context, req *pb.ListLikedYouRequest) (*pb.ListLikedYouResponse, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		f.authorization = md.Get("authorization")[0]
+	}
+
+	page := 0
+	if req.PaginationToken != nil {
+		page, _ = strconv.Atoi(*req.PaginationToken)
+	}
+	resp := &pb.ListLikedYouResponse{
+		Likers: []*pb.ListLikedYouResponse_Liker{{ActorId: "liker" + strconv.Itoa(page), UnixTimestamp: 1700000000}},
+	}
+	if req.RecipientUserId == "free" {
+		resp.Likers[0].Handle, resp.Likers[0].ActorId = "handle", ""
+		resp.Redacted = true
+	}
+	// Like the service, the last page has an empty rather than missing token
+	next := ""
+	if page < 2 {
+		next = strconv.Itoa(page + 1)
+	}
+	resp.NextPaginationToken = &next
+	return resp, nil
+}
+
+func (f *fakeServer) CountLikedYou(ctx context.Context, req *pb.CountLikedYouRequest) (*pb.CountLikedYouResponse, error) {
+	if req.RecipientUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "recipient_user_id is required")
+	}
+	return &pb.CountLikedYouResponse{Count: 3}, nil
+}
+
+func startServer(t *testing.T) (*fakeServer, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	fake := &fakeServer{}
+	server := grpc.NewServer()
+	pb.RegisterExploreServiceServer(server, fake)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return fake, lis.Addr().String()
+}
+
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestLikersAll(t *testing.T) {
+	fake, addr := startServer(t)
+
+	code, stdout, stderr := runCLI("likers", "user2", "-all", "-addr", addr, "-token", "secret")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "LIKER   LIKED AT              SUPER  SEEN\n"+
+		"liker0  2023-11-14T22:13:20Z  no     no\n"+
+		"liker1  2023-11-14T22:13:20Z  no     no\n"+
+		"liker2  2023-11-14T22:13:20Z  no     no\n", stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, "Bearer secret", fake.authorization)
+
+	// Without -all only the first page is listed, with a hint for the next one
+	code, stdout, stderr = runCLI("likers", "-addr", addr, "user2")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "liker0")
+	assert.NotContains(t, stdout, "liker1")
+	assert.Contains(t, stderr, "-page-token 1")
+}
+
+func TestOutput(t *testing.T) {
+	_, addr := startServer(t)
+
+	code, stdout, _ := runCLI("count", "-addr", addr, "-output", "json", "user2")
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{"count": "3"}`, stdout)
+
+	// liker2 is on the last page of user1's likers
+	code, stdout, _ = runCLI("relationship", "-addr", addr, "liker2", "user1")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "USER    ABOUT   LIKED  LIKED AT              SUPER\n"+
+		"liker2  user1   yes    2023-11-14T22:13:20Z  no\n"+
+		"user1   liker2  no     -                     -\n", stdout)
+
+	code, stdout, _ = runCLI("relationship", "-addr", addr, "-output", "json", "liker2", "user1")
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{
+		"likes": [
+			{"actor_id": "liker2", "recipient_id": "user1", "liked": true, "unix_timestamp": 1700000000, "super_like": false},
+			{"actor_id": "user1", "recipient_id": "liker2", "liked": false}
+		],
+		"mutual_likes": false
+	}`, stdout)
+}
+
+func TestErrors(t *testing.T) {
+	_, addr := startServer(t)
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{
+			name:   "unknown command",
+			args:   []string{"frobnicate"},
+			code:   2,
+			stderr: `unknown command "frobnicate"`,
+		},
+		{
+			name:   "missing arguments",
+			args:   []string{"relationship", "user1"},
+			code:   2,
+			stderr: "expects 2 argument(s): USER OTHER",
+		},
+		{
+			name:   "unknown output format",
+			args:   []string{"count", "-output", "yaml", "user2"},
+			code:   2,
+			stderr: `unknown output format "yaml"`,
+		},
+		{
+			name:   "server error",
+			args:   []string{"count", "-addr", addr, ""},
+			code:   1,
+			stderr: "InvalidArgument: recipient_user_id is required",
+		},
+		{
+			name:   "redacted likers",
+			args:   []string{"relationship", "-addr", addr, "user1", "free"},
+			code:   1,
+			stderr: "can't tell who liked free",
+		},
+		{
+			name:   "invalid time",
+			args:   []string{"likers", "-addr", addr, "-since", "yesterday", "user2"},
+			code:   1,
+			stderr: `invalid -since "yesterday"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// printer writes command results as aligned tables or protobuf JSON
+type printer struct {
+	out    io.Writer
+	errOut io.Writer
+	json   bool
+}
+
+func newPrinter(out, errOut io.Writer, format string) (printer, error) {
+	switch format {
+	case "table":
+		return printer{out: out, errOut: errOut}, nil
+	case "json":
+		return printer{out: out, errOut: errOut, json: true}, nil
+	default:
+		return printer{}, fmt.Errorf("unknown output format %q, expected table or json", format)
+	}
+}
+
+// print writes msg as JSON, or as a table with the given header and rows
+func (p printer) print(msg proto.Message, header []string, rows [][]string) error {
+	if p.json {
+		raw, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", EmitUnpopulated: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, string(raw))
+		return err
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// note writes information for the operator that isn't part of the result
+func (p printer) note(format string, args ...any) {
+	fmt.Fprintf(p.errOut, format+"\n", args...)
+}
+
+func formatTime(unix uint64) string {
+	if unix == 0 {
+		return "-"
+	}
+	return time.Unix(int64(unix), 0).UTC().Format(time.RFC3339)
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
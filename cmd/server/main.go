@@ -2,51 +2,259 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"muzz-explore-service/internal/app"
 	"muzz-explore-service/internal/config"
 	"muzz-explore-service/internal/db"
-	"muzz-explore-service/internal/server"
-	"muzz-explore-service/internal/service"
+	"muzz-explore-service/internal/events"
+	"muzz-explore-service/internal/expiry"
+	"muzz-explore-service/internal/gateway"
+	"muzz-explore-service/internal/health"
+	"muzz-explore-service/internal/logging"
+	"muzz-explore-service/internal/tracing"
+	"muzz-explore-service/internal/web"
+	pb "muzz-explore-service/pkg/pb/proto"
 )
 
+const usage = `usage: muzz-explore-service [flags] [migrate|scores|verify ...]
+
+Serves the ExploreService, or runs a maintenance subcommand. Settings come from
+defaults, then the YAML file given by -config, then environment variables, then
+flags.
+
+flags:
+`
+
 func main() {
-	cfg := config.Load()
+	fs := flag.NewFlagSet("muzz-explore-service", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	printConfig := fs.Bool("print-config", false, "print the effective configuration as YAML, with secrets redacted, and exit")
+	cfg, err := config.Load(fs, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *printConfig {
+		if err := cfg.Write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	args := fs.Args()
+
+	logger, err := logging.New(os.Stderr, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
+
+	redactor, err := logging.NewUserIDRedactor(cfg.LogUserIDs, cfg.LogUserIDKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "scores" {
+		if err := runScores(cfg, args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "verify" {
+		if err := runVerify(cfg, args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Refuse to serve against an unmigrated database
+	if err := ensureSchema(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize tracing
+	tp, shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Initialize postgres connection
-	pool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer(tp)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer pool.Close()
 
-	// Create queries
-	queries := db.New(pool)
+	// Create the service and server
+	explore, err := app.New(cfg, pool, logger, redactor, tp)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, queries, m, httpTLSConfig := explore.Server, explore.Queries, explore.Metrics, explore.HTTPTLSConfig
 
-	// Initialize service
-	exploreService := service.NewExploreService(queries)
+	// Keep the health service in line with database availability
+	probeCtx, stopProbes := context.WithCancel(context.Background())
+	defer stopProbes()
+	prober := health.NewProber(srv.Health(), []string{pb.ExploreService_ServiceDesc.ServiceName}, cfg.HealthProbeInterval, logger,
+		health.Check{Name: "database", Probe: pool.Ping},
+		health.Check{Name: "schema", Probe: func(ctx context.Context) error { return db.CheckSchemaVersion(ctx, pool) }},
+	)
+	go prober.Run(probeCtx)
 
-	// Create and start server
-	srv := server.NewGRPCServer(exploreService)
+	// Archive expired likes in the background
+	sweepCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	if cfg.LikeExpiry > 0 {
+		publisher := events.NewLogPublisher(logger, redactor)
+		sweeper := expiry.NewSweeper(queries, publisher, cfg.LikeExpiry, cfg.LikeExpirySweepInterval, logger)
+		go sweeper.Run(sweepCtx)
+	}
 
 	// Handle graceful shutdown
+	logger.Info("starting gRPC server", "port", cfg.GRPCPort, "metrics_port", cfg.MetricsPort)
 	go func() {
 		if err := srv.Start(cfg.GRPCPort); err != nil {
 			log.Fatalf("failed to serve: %v", err)
 		}
 	}()
 
+	corsConfig := web.CORSConfig{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		MaxAge:         cfg.CORSMaxAge,
+	}
+
+	// Serve the REST/JSON gateway
+	var gatewayServer *http.Server
+	if cfg.HTTPPort != 0 {
+		conn, err := srv.DialInProcess()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer conn.Close()
+		handler, err := gateway.New(context.Background(), conn)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		gatewayServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.HTTPPort),
+			Handler:           web.CORS(corsConfig, handler),
+			TLSConfig:         httpTLSConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		logger.Info("starting HTTP gateway", "port", cfg.HTTPPort)
+		go func() {
+			var err error
+			if httpTLSConfig != nil {
+				err = gatewayServer.ListenAndServeTLS("", "")
+			} else {
+				err = gatewayServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("failed to serve HTTP gateway: %v", err)
+			}
+		}()
+	}
+
+	// Serve Connect and gRPC-Web for browser clients, along with gRPC, over h2c
+	var connectServer *http.Server
+	if cfg.ConnectPort != 0 {
+		handler, err := srv.ConnectHandler()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		connectServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.ConnectPort),
+			Handler:           web.NewHandler(handler, corsConfig),
+			TLSConfig:         httpTLSConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		logger.Info("starting Connect server", "port", cfg.ConnectPort)
+		go func() {
+			var err error
+			if httpTLSConfig != nil {
+				err = connectServer.ListenAndServeTLS("", "")
+			} else {
+				err = connectServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("failed to serve Connect: %v", err)
+			}
+		}()
+	}
+
+	metricsServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.MetricsPort),
+		Handler:           m.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to serve metrics: %v", err)
+		}
+	}()
+
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
 
-	// Graceful shutdown
-	log.Println("shutting down gRPC server...")
+	// Graceful shutdown. The gateway and Connect servers call through the gRPC
+	// server, so drain them before stopping it
+	stopProbes()
+	stopSweeper()
+	srv.Health().Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if gatewayServer != nil {
+		logger.Info("shutting down HTTP gateway")
+		if err := gatewayServer.Shutdown(ctx); err != nil {
+			logger.Error("failed to shut down HTTP gateway", "error", err)
+		}
+	}
+	if connectServer != nil {
+		logger.Info("shutting down Connect server")
+		if err := connectServer.Shutdown(ctx); err != nil {
+			logger.Error("failed to shut down Connect server", "error", err)
+		}
+	}
+
+	logger.Info("shutting down gRPC server")
 	srv.GracefulStop()
+
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		logger.Error("failed to shut down metrics server", "error", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
 }
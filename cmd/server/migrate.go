@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/db"
+)
+
+const migrateUsage = "usage: muzz-explore-service migrate up|down [steps]|status"
+
+// runMigrate handles the `migrate` subcommand
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := db.NewMigrator(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q: %v", args[1], err)
+			}
+		}
+		if err := migrator.Down(steps); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version: %d\nlatest: %d\ndirty: %t\nup to date: %t\n", status.Version, status.Latest, status.Dirty, status.UpToDate())
+	return nil
+}
+
+// ensureSchema verifies the database is at the latest migration before serving.
+// If AutoMigrate is enabled, pending migrations are applied instead of failing.
+func ensureSchema(cfg *config.Config) error {
+	migrator, err := db.NewMigrator(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if cfg.AutoMigrate {
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	}
+
+	return migrator.CheckVersion()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/scoring"
+)
+
+const scoresUsage = "usage: muzz-explore-service scores recompute"
+
+// lockScores stops decisions from updating scores until the recompute commits,
+// so the recomputed scores can't overwrite them
+const lockScores = `LOCK TABLE user_scores IN EXCLUSIVE MODE`
+
+// runScores handles the `scores` subcommand
+func runScores(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "recompute" {
+		return errors.New(scoresUsage)
+	}
+
+	if err := ensureSchema(cfg); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	var written int
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockScores); err != nil {
+			return fmt.Errorf("failed to lock scores: %w", err)
+		}
+		written, err = scoring.NewScorer(db.New(tx)).Recompute(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("recomputed %d user scores\n", written)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/verify"
+)
+
+// runVerify handles the `verify` subcommand, printing the JSON report to
+// stdout and a summary to stderr
+func runVerify(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "fix the anomalies found")
+	samples := flags.Int("samples", verify.DefaultSamples, "example rows to report per check")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := ensureSchema(cfg); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	report, err := verify.NewVerifier(pool, *samples).Run(ctx, *repair)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	if err := report.WriteSummary(os.Stderr); err != nil {
+		return err
+	}
+
+	if !report.Clean() {
+		return errors.New("found anomalies, rerun with -repair to fix them")
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Package app wires the ExploreService and its gRPC server from configuration,
+// so the server command and the end-to-end tests run the same stack
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/deck"
+	"muzz-explore-service/internal/entitlements"
+	"muzz-explore-service/internal/logging"
+	"muzz-explore-service/internal/metrics"
+	"muzz-explore-service/internal/quota"
+	"muzz-explore-service/internal/ratelimit"
+	"muzz-explore-service/internal/scoring"
+	"muzz-explore-service/internal/server"
+	"muzz-explore-service/internal/service"
+	"muzz-explore-service/internal/tlsconfig"
+	"muzz-explore-service/internal/visibility"
+)
+
+// App is the service and gRPC server, with the dependencies the server command
+// shares with its HTTP servers and background jobs
+type App struct {
+	Server    *server.Server
+	Queries   *db.Queries
+	Metrics   *metrics.Metrics
+	TLSConfig *tls.Config // Nil when TLS is disabled
+
+	// HTTPTLSConfig serves the same certificate to the HTTP listeners without
+	// asking for client certificates, which browsers can't present and the
+	// HTTP listeners don't authenticate. Nil when TLS is disabled.
+	HTTPTLSConfig *tls.Config
+}
+
+// New builds the service and gRPC server described by cfg, backed by pool
+func New(cfg *config.Config, pool *pgxpool.Pool, logger *slog.Logger, redactor logging.UserIDRedactor, tp trace.TracerProvider) (*App, error) {
+	// Initialize metrics
+	m := metrics.New()
+	m.Register(metrics.NewPoolCollector(pool))
+
+	// Create queries
+	queries := db.New(pool)
+
+	serviceOpts := []service.Option{
+		service.WithDecisionObserver(m),
+		service.WithLogger(logger),
+	}
+	serverOpts := []server.Option{
+		server.WithTracing(tp),
+		server.WithLogging(logger, redactor),
+		server.WithMetrics(m),
+		server.WithMaxRecvMsgSize(int(cfg.GRPCMaxRecvMsgSize)),
+	}
+
+	// Initialize TLS
+	var tlsConfig, httpTLSConfig *tls.Config
+	if cfg.TLSCertFile != "" {
+		var err error
+		tlsConfig, err = tlsconfig.New(tlsconfig.Config{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.TLSClientCAFile,
+			ClientAuth:   cfg.TLSClientAuth,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		httpTLSConfig, err = tlsconfig.New(tlsconfig.Config{
+			CertFile: cfg.TLSCertFile,
+			KeyFile:  cfg.TLSKeyFile,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		serverOpts = append(serverOpts, server.WithTLS(tlsConfig))
+	} else {
+		logger.Warn("TLS is disabled")
+	}
+
+	// Initialize authentication. Bearer tokens take precedence, so a backend
+	// forwarding a user's token acts as that user rather than as itself.
+	var authenticators []auth.Authenticator
+	switch cfg.AuthMode {
+	case "none":
+	case "jwt":
+		authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{
+			JWKSFile:  cfg.AuthJWKSFile,
+			StaticKey: cfg.AuthStaticKey,
+			Issuer:    cfg.AuthIssuer,
+			Audience:  cfg.AuthAudience,
+		})
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, authenticator)
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", cfg.AuthMode)
+	}
+	if cfg.TLSCertFile != "" && cfg.TLSClientCAFile != "" {
+		authenticators = append(authenticators, auth.CertificateAuthenticator{})
+	}
+	if len(authenticators) > 0 {
+		serviceOpts = append(serviceOpts, service.WithAuthorization())
+		serverOpts = append(serverOpts, server.WithAuthenticator(auth.Chain(authenticators...)))
+	} else {
+		logger.Warn("authentication is disabled")
+	}
+
+	// Initialize rate limiting
+	if cfg.RateLimitEnabled {
+		limits, err := ratelimit.ParseLimits(cfg.RateLimits)
+		if err != nil {
+			return nil, err
+		}
+		defaultLimit, err := ratelimit.ParseLimit(cfg.RateLimitDefault)
+		if err != nil {
+			return nil, err
+		}
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), limits, &defaultLimit)
+		serverOpts = append(serverOpts, server.WithRateLimiter(limiter))
+	}
+
+	// Initialize like quota
+	userEntitlements := entitlements.NewStatic(cfg.PremiumUserIDs)
+	if cfg.LikeQuotaLimit > 0 {
+		serviceOpts = append(serviceOpts, service.WithQuota(
+			quota.NewManager(queries, userEntitlements, cfg.LikeQuotaLimit, cfg.LikeQuotaWindow),
+		))
+	}
+
+	// Initialize likes visibility gate
+	if cfg.LikesVisibilityGate {
+		serviceOpts = append(serviceOpts, service.WithVisibilityPolicy(
+			visibility.NewPolicy(userEntitlements, []byte(cfg.VisibilityHandleKey)),
+		))
+	}
+
+	// Initialize explore deck
+	if cfg.CandidatesFile != "" {
+		candidates, err := deck.LoadFile(cfg.CandidatesFile)
+		if err != nil {
+			return nil, err
+		}
+		serviceOpts = append(serviceOpts, service.WithCandidateSource(candidates))
+	}
+
+	// Initialize desirability scores
+	if cfg.ScoringEnabled {
+		serviceOpts = append(serviceOpts, service.WithScorer(scoring.NewScorer(queries)))
+	}
+
+	// Initialize like expiry
+	if cfg.LikeExpiry > 0 {
+		serviceOpts = append(serviceOpts, service.WithLikeExpiry(cfg.LikeExpiry))
+	}
+
+	// Initialize service and server
+	exploreService := service.NewExploreService(queries, serviceOpts...)
+
+	return &App{
+		Server:        server.NewGRPCServer(exploreService, serverOpts...),
+		Queries:       queries,
+		Metrics:       m,
+		TLSConfig:     tlsConfig,
+		HTTPTLSConfig: httpTLSConfig,
+	}, nil
+}
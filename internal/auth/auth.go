@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RoleInternal is carried by trusted backend services, which may act on behalf of any user
+const RoleInternal = "internal"
+
+// Principal is the authenticated identity making a request
+type Principal struct {
+	Subject string   // The user ID, or service name for internal callers
+	Roles   []string // Roles granted to the caller, e.g. RoleInternal
+}
+
+// HasRole reports whether the principal was granted the given role
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// Authenticator identifies the caller of an RPC from its context, typically
+// from credentials in the incoming metadata
+type Authenticator interface {
+	Authenticate(ctx context.Context) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated principal
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// AuthorizeUser checks that the caller may act as userID: either the caller
+// is that user, or it carries the internal service role
+func AuthorizeUser(ctx context.Context, userID string) error {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if p.Subject != userID && !p.HasRole(RoleInternal) {
+		return status.Error(codes.PermissionDenied, "caller may not act on behalf of another user")
+	}
+	return nil
+}
+
+// AuthorizeInternal checks that the caller carries the internal service role
+func AuthorizeInternal(ctx context.Context) error {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if !p.HasRole(RoleInternal) {
+		return status.Error(codes.PermissionDenied, "caller is not an internal service")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func withClientCert(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{}
+	if cert != nil {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: state},
+	})
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	spiffe, err := url.Parse("spiffe://muzz/matcher")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		want     string
+		wantCode codes.Code
+	}{
+		{
+			name: "URI SAN",
+			ctx:  withClientCert(&x509.Certificate{URIs: []*url.URL{spiffe}, DNSNames: []string{"matcher.internal"}}),
+			want: "spiffe://muzz/matcher",
+		},
+		{
+			name: "DNS SAN",
+			ctx:  withClientCert(&x509.Certificate{DNSNames: []string{"matcher.internal"}}),
+			want: "matcher.internal",
+		},
+		{
+			name: "common name",
+			ctx:  withClientCert(&x509.Certificate{Subject: pkix.Name{CommonName: "matcher"}}),
+			want: "matcher",
+		},
+		{
+			name:     "no client certificate",
+			ctx:      withClientCert(nil),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "no peer",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CertificateAuthenticator{}.Authenticate(tt.ctx)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Subject)
+			assert.True(t, got.HasRole(RoleInternal))
+		})
+	}
+}
+
+type authenticatorFunc func(ctx context.Context) (*Principal, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context) (*Principal, error) {
+	return f(ctx)
+}
+
+func TestChain(t *testing.T) {
+	fail := authenticatorFunc(func(context.Context) (*Principal, error) {
+		return nil, status.Error(codes.Unauthenticated, "nope")
+	})
+	succeed := authenticatorFunc(func(context.Context) (*Principal, error) {
+		return &Principal{Subject: "user1"}, nil
+	})
+
+	got, err := Chain(fail, succeed).Authenticate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "user1", got.Subject)
+
+	_, err = Chain(fail, authenticatorFunc(func(context.Context) (*Principal, error) {
+		return nil, errors.New("last")
+	})).Authenticate(context.Background())
+	assert.EqualError(t, err, "last")
+
+	_, err = Chain().Authenticate(context.Background())
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testKey = "test-secret"
+
+func signHS256(t *testing.T, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testKey))
+	require.NoError(t, err)
+	return token
+}
+
+func withBearer(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func validClaims(subject string, roles ...string) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    "muzz",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: roles,
+	}
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{StaticKey: testKey, Issuer: "muzz"})
+	require.NoError(t, err)
+
+	expired := validClaims("user1")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	wrongIssuer := validClaims("user1")
+	wrongIssuer.Issuer = "someone-else"
+
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims("user1")).SignedString([]byte("wrong-secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		want     *Principal
+		wantCode codes.Code
+	}{
+		{
+			name: "valid token",
+			ctx:  withBearer(signHS256(t, validClaims("user1"))),
+			want: &Principal{Subject: "user1"},
+		},
+		{
+			name: "internal service role",
+			ctx:  withBearer(signHS256(t, validClaims("matcher", RoleInternal))),
+			want: &Principal{Subject: "matcher", Roles: []string{RoleInternal}},
+		},
+		{
+			name:     "missing metadata",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "not a bearer token",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic dXNlcjpwYXNz")),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "expired token",
+			ctx:      withBearer(signHS256(t, expired)),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong issuer",
+			ctx:      withBearer(signHS256(t, wrongIssuer)),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong signing key",
+			ctx:      withBearer(forged),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authenticator.Authenticate(tt.ctx)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJWTAuthenticator_JWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	set := map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{JWKSFile: path})
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims("user1"))
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	got, err := authenticator.Authenticate(withBearer(signed))
+	require.NoError(t, err)
+	assert.Equal(t, "user1", got.Subject)
+
+	// HMAC tokens must not be accepted when verifying with public keys
+	_, err = authenticator.Authenticate(withBearer(signHS256(t, validClaims("user1"))))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthorizeUser(t *testing.T) {
+	user := WithPrincipal(context.Background(), &Principal{Subject: "user1"})
+	internal := WithPrincipal(context.Background(), &Principal{Subject: "matcher", Roles: []string{RoleInternal}})
+
+	assert.NoError(t, AuthorizeUser(user, "user1"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(AuthorizeUser(user, "user2")))
+	assert.NoError(t, AuthorizeUser(internal, "user2"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(AuthorizeUser(context.Background(), "user1")))
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv unsets every setting's environment variable for the test
+func clearEnv(t *testing.T) {
+	for _, s := range new(Config).settings() {
+		if value, ok := os.LookupEnv(s.env); ok {
+			t.Setenv(s.env, value) // Restores the value after the test
+			os.Unsetenv(s.env)
+		}
+	}
+	t.Setenv("CONFIG_FILE", "")
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.GRPCPort)
+	assert.Equal(t, "text", cfg.LogFormat)
+	assert.Equal(t, 10*time.Second, cfg.HealthProbeInterval)
+	assert.Equal(t, 4*MiB, cfg.GRPCMaxRecvMsgSize)
+	assert.Equal(t, "explore-service", cfg.ServiceName)
+	assert.Empty(t, cfg.PremiumUserIDs)
+	assert.Equal(t, "redact", cfg.LogUserIDs)
+	assert.False(t, cfg.RateLimitEnabled, "rate limits are opt-in")
+	assert.Zero(t, cfg.LikeQuotaLimit, "the like quota is opt-in")
+	assert.False(t, cfg.LikesVisibilityGate, "the visibility gate is opt-in")
+}
+
+func TestLoad_Layers(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, `
+grpc_port: 9000
+metrics_port: 9100
+http_port: 0
+environment: production
+premium_user_ids: [user1, " user2 "]
+like_quota_window: 12h
+grpc_max_recv_msg_size: 16MiB
+`)
+	t.Setenv("METRICS_PORT", "9200")
+	t.Setenv("LIKE_QUOTA_WINDOW", "6h")
+
+	fs := newFlagSet()
+	cfg, err := Load(fs, []string{"-config", path, "-like-quota-window", "1h", "-scoring-enabled=false", "migrate", "up"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.GRPCPort, "from the file")
+	assert.Equal(t, 9200, cfg.MetricsPort, "the environment overrides the file")
+	assert.Equal(t, time.Hour, cfg.LikeQuotaWindow, "flags override the environment")
+	assert.Equal(t, 0, cfg.HTTPPort)
+	assert.False(t, cfg.ScoringEnabled)
+	assert.Equal(t, []string{"user1", "user2"}, cfg.PremiumUserIDs)
+	assert.Equal(t, 16*MiB, cfg.GRPCMaxRecvMsgSize)
+	assert.Equal(t, "json", cfg.LogFormat, "production defaults to JSON logs")
+	assert.Equal(t, []string{"migrate", "up"}, fs.Args())
+
+	// CONFIG_FILE names the file when there's no flag
+	t.Setenv("CONFIG_FILE", path)
+	cfg, err = Load(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.GRPCPort)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		err  string
+	}{
+		{
+			name: "unparsable port",
+			env:  map[string]string{"GRPC_PORT": "80a"},
+			err:  "invalid configuration:\nenvironment variable GRPC_PORT: invalid integer \"80a\"",
+		},
+		{
+			name: "every problem is reported",
+			env:  map[string]string{"LIKE_EXPIRY": "1 day", "AUTO_MIGRATE": "yes"},
+			args: []string{"-grpc-max-recv-msg-size", "4 parsecs"},
+			err: "invalid configuration:\n" +
+				"environment variable AUTO_MIGRATE: invalid boolean \"yes\", expected true or false\n" +
+				"environment variable LIKE_EXPIRY: invalid duration \"1 day\", expected a value such as 30s, 5m or 24h\n" +
+				"flag -grpc-max-recv-msg-size: invalid size \"4 parsecs\", expected a number of bytes with an optional unit such as KiB, MiB or GB",
+		},
+		{
+			name: "unknown key in file",
+			file: "grpc_port: 9000\ngrpc_prot: 9001\n",
+			err:  "invalid configuration:\nconfig file FILE, line 2: unknown setting \"grpc_prot\"",
+		},
+		{
+			name: "list for a single value",
+			file: "log_level: [debug]\n",
+			err:  "invalid configuration:\nconfig file FILE, line 1: log_level: expected a single value, not a list",
+		},
+		{
+			name: "out of range",
+			env:  map[string]string{"GRPC_PORT": "70000", "TRACE_SAMPLE_RATIO": "2", "LOG_FORMAT": "xml"},
+			err: "invalid configuration:\n" +
+				"grpc_port (GRPC_PORT) must be between 1 and 65535, got 70000\n" +
+				"log_format (LOG_FORMAT) must be one of json, text, got \"xml\"\n" +
+				"trace_sample_ratio (TRACE_SAMPLE_RATIO) must be between 0 and 1, got 2",
+		},
+		{
+			name: "inconsistent",
+			env:  map[string]string{"HTTP_PORT": "8080", "AUTH_MODE": "jwt", "TLS_CERT_FILE": "server.pem"},
+			err: "invalid configuration:\n" +
+				"http_port (HTTP_PORT) uses port 8080, already used by grpc_port\n" +
+				"auth_mode (AUTH_MODE) jwt needs exactly one of auth_jwks_file (AUTH_JWKS_FILE) or auth_static_key (AUTH_STATIC_KEY)\n" +
+				"tls_key_file (TLS_KEY_FILE) and tls_cert_file (TLS_CERT_FILE) must be set together",
+		},
+		{
+			name: "hashed user IDs without a key",
+			env:  map[string]string{"LOG_USER_IDS": "hash"},
+			err:  "invalid configuration:\nlog_user_ids (LOG_USER_IDS) hash needs log_user_id_key (LOG_USER_ID_KEY)",
+		},
+		{
+			name: "malformed rate limits",
+			env:  map[string]string{"RATE_LIMITS": "PutDecision=2:30,ListLikedYou", "RATE_LIMIT_DEFAULT": "10"},
+			err: "invalid configuration:\n" +
+				"rate_limits (RATE_LIMITS) invalid rate limit entry \"ListLikedYou\", want Method=rate:burst\n" +
+				"rate_limit_default (RATE_LIMIT_DEFAULT) invalid rate limit \"10\", want rate:burst",
+		},
+		{
+			name: "rate limit out of range",
+			env:  map[string]string{"RATE_LIMITS": "PutDecision=0:30", "RATE_LIMIT_DEFAULT": "10:0"},
+			err: "invalid configuration:\n" +
+				"rate_limits (RATE_LIMITS) invalid rate in rate limit \"0:30\"\n" +
+				"rate_limit_default (RATE_LIMIT_DEFAULT) invalid burst in rate limit \"10:0\"",
+		},
+		{
+			name: "visibility gate without a handle key",
+			env:  map[string]string{"LIKES_VISIBILITY_GATE": "true"},
+			err:  "invalid configuration:\nlikes_visibility_gate (LIKES_VISIBILITY_GATE) needs visibility_handle_key (VISIBILITY_HANDLE_KEY), so liker handles stay stable across replicas and restarts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			expected := tt.err
+			if tt.file != "" {
+				path := writeFile(t, tt.file)
+				args = append([]string{"-config", path}, args...)
+				expected = strings.ReplaceAll(expected, "FILE", path)
+			}
+
+			_, err := Load(newFlagSet(), args)
+			assert.EqualError(t, err, expected)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DATABASE_URL", "postgres://explore:hunter2@db:5432/explore?sslmode=disable")
+	t.Setenv("AUTH_STATIC_KEY", "hmac-secret")
+	t.Setenv("PREMIUM_USER_IDS", "user1,user2")
+
+	cfg, err := Load(nil, nil)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.Write(&out))
+	printed := out.String()
+	assert.Contains(t, printed, "database_url: postgres://explore:REDACTED@db:5432/explore?sslmode=disable\n")
+	assert.Contains(t, printed, "auth_static_key: REDACTED\n")
+	assert.Contains(t, printed, "visibility_handle_key: \"\"\n", "unset secrets show as empty")
+	assert.Contains(t, printed, "premium_user_ids: [user1, user2]\n")
+	assert.Contains(t, printed, "health_probe_interval: 10s\n")
+	assert.Contains(t, printed, "grpc_max_recv_msg_size: 4MiB\n")
+	assert.NotContains(t, printed, "hunter2")
+	assert.NotContains(t, printed, "hmac-secret")
+
+	// The printed configuration loads back as a config file, secrets aside
+	t.Setenv("CONFIG_FILE", writeFile(t, printed))
+	loaded, err := Load(nil, nil)
+	require.NoError(t, err)
+	cfg.DatabaseURL, cfg.AuthStaticKey = loaded.DatabaseURL, loaded.AuthStaticKey
+	assert.Equal(t, cfg, loaded)
+}
+
+func TestRedactURL(t *testing.T) {
+	assert.Equal(t, "postgres://explore@db/explore", redactURL("postgres://explore@db/explore"))
+	assert.Equal(t, "postgres://db/explore?password=REDACTED&user=explore", redactURL("postgres://db/explore?user=explore&password=secret"))
+	assert.Equal(t, "host=db user=explore password=REDACTED dbname=explore", redactURL("host=db user=explore password=secret dbname=explore"))
+	assert.Equal(t, "host=db password=REDACTED", redactURL(`host=db password='it\'s secret'`))
+	assert.Equal(t, "REDACTED", redactURL("postgres://explore:%zz@db/explore"))
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		size  Size
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"64KiB", 64 * KiB},
+		{"4 MiB", 4 * MiB},
+		{"1GiB", GiB},
+		{"2MB", 2_000_000},
+	}
+	for _, tt := range tests {
+		size, err := ParseSize(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.size, size, tt.value)
+	}
+
+	for _, invalid := range []string{"", "MiB", "-1KiB", "1.5MiB", "4TiB", "99999999999GiB"} {
+		_, err := ParseSize(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	assert.Equal(t, "4MiB", (4 * MiB).String())
+	assert.Equal(t, "1536KiB", (1536 * KiB).String())
+	assert.Equal(t, "1000B", Size(1000).String())
+	assert.Equal(t, "0B", Size(0).String())
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in printed configurations
+const redacted = "REDACTED"
+
+// dsnPassword matches the password in a key=value connection string
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Write prints the configuration as YAML that can be used as a config file,
+// with secrets redacted
+func (c *Config) Write(w io.Writer) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range c.settings() {
+		root.Content = append(root.Content, scalar("!!str", s.key), s.node())
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// node renders the setting's current value, redacted as its secrecy requires
+func (s setting) node() *yaml.Node {
+	switch target := s.target.(type) {
+	case *string:
+		switch {
+		case *target == "" || s.secrecy == public:
+			return scalar("!!str", *target)
+		case s.secrecy == secretURL:
+			return scalar("!!str", redactURL(*target))
+		default:
+			return scalar("!!str", redacted)
+		}
+	case *int:
+		return scalar("!!int", strconv.Itoa(*target))
+	case *bool:
+		return scalar("!!bool", strconv.FormatBool(*target))
+	case *float64:
+		return scalar("!!float", strconv.FormatFloat(*target, 'g', -1, 64))
+	case *time.Duration:
+		return scalar("!!str", target.String())
+	case *Size:
+		return scalar("!!str", target.String())
+	case *[]string:
+		list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, item := range *target {
+			list.Content = append(list.Content, scalar("!!str", item))
+		}
+		return list
+	default:
+		panic("unsupported setting type")
+	}
+}
+
+// redactURL hides the password in a connection URL or key=value connection string
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return dsnPassword.ReplaceAllString(value, "${1}"+redacted)
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return redacted // Can't tell where the password is
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	if query := u.Query(); query.Has("password") {
+		query.Set("password", redacted)
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
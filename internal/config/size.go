@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is a number of bytes
+type Size int64
+
+const (
+	Byte Size = 1
+	KiB       = 1024 * Byte
+	MiB       = 1024 * KiB
+	GiB       = 1024 * MiB
+)
+
+var sizeUnits = []struct {
+	suffix string
+	size   Size
+}{
+	// Bytes come last, as every other unit also ends in B
+	{"KiB", KiB}, {"MiB", MiB}, {"GiB", GiB},
+	{"KB", 1000}, {"MB", 1000 * 1000}, {"GB", 1000 * 1000 * 1000},
+	{"B", Byte},
+}
+
+// ParseSize parses a size such as 512, 64KiB, 4MiB or 1GB. Binary units
+// (KiB, MiB, GiB) are powers of 1024 and decimal units (KB, MB, GB) powers of 1000.
+func ParseSize(value string) (Size, error) {
+	value = strings.TrimSpace(value)
+	number, unit := value, Byte
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(value, u.suffix) {
+			number, unit = strings.TrimSpace(strings.TrimSuffix(value, u.suffix)), u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 || n > int64(1<<62)/int64(unit) {
+		return 0, fmt.Errorf("invalid size %q, expected a number of bytes with an optional unit such as KiB, MiB or GB", value)
+	}
+	return Size(n) * unit, nil
+}
+
+// String formats the size in the largest binary unit that divides it exactly
+func (s Size) String() string {
+	for _, u := range []struct {
+		suffix string
+		size   Size
+	}{{"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}} {
+		if s != 0 && s%u.size == 0 {
+			return fmt.Sprintf("%d%s", s/u.size, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", int64(s))
+}
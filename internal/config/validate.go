@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"muzz-explore-service/internal/ratelimit"
+)
+
+// validator collects every problem with a configuration, naming settings by
+// both their config file key and environment variable
+type validator struct {
+	envs map[string]string
+	errs []error
+}
+
+func (v *validator) fail(key, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s (%s) %s", key, v.envs[key], fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
+
+func (v *validator) port(key string, port int, optional bool) {
+	minPort := 1
+	if optional {
+		minPort = 0
+	}
+	if port < minPort || port > math.MaxUint16 {
+		v.fail(key, "must be between %d and %d, got %d", minPort, math.MaxUint16, port)
+	}
+}
+
+// Validate checks every setting is in range and consistent with the others,
+// reporting all problems at once
+func (c *Config) Validate() error {
+	v := &validator{envs: map[string]string{}}
+	for _, s := range c.settings() {
+		v.envs[s.key] = s.env
+	}
+
+	if c.DatabaseURL == "" {
+		v.fail("database_url", "is required")
+	}
+	if c.Environment == "" {
+		v.fail("environment", "is required")
+	}
+
+	v.port("grpc_port", c.GRPCPort, false)
+	v.port("metrics_port", c.MetricsPort, false)
+	v.port("http_port", c.HTTPPort, true)
+	v.port("connect_port", c.ConnectPort, true)
+	ports := map[int]string{}
+	for _, p := range []struct {
+		key  string
+		port int
+	}{{"grpc_port", c.GRPCPort}, {"metrics_port", c.MetricsPort}, {"http_port", c.HTTPPort}, {"connect_port", c.ConnectPort}} {
+		if p.port == 0 {
+			continue
+		}
+		if other, ok := ports[p.port]; ok {
+			v.fail(p.key, "uses port %d, already used by %s", p.port, other)
+		}
+		ports[p.port] = p.key
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		v.fail("log_level", "must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+	v.oneOf("log_format", c.LogFormat, "json", "text")
+	v.oneOf("log_user_ids", c.LogUserIDs, "plain", "hash", "redact")
+	if c.LogUserIDs == "hash" && c.LogUserIDKey == "" {
+		v.fail("log_user_ids", "hash needs log_user_id_key (LOG_USER_ID_KEY)")
+	}
+
+	if c.HealthProbeInterval <= 0 {
+		v.fail("health_probe_interval", "must be positive, got %s", c.HealthProbeInterval)
+	}
+	if c.ShutdownTimeout <= 0 {
+		v.fail("shutdown_timeout", "must be positive, got %s", c.ShutdownTimeout)
+	}
+	if c.GRPCMaxRecvMsgSize <= 0 || c.GRPCMaxRecvMsgSize > math.MaxInt32 {
+		v.fail("grpc_max_recv_msg_size", "must be between 1B and 2GiB, got %s", c.GRPCMaxRecvMsgSize)
+	}
+
+	v.oneOf("auth_mode", c.AuthMode, "none", "jwt")
+	if c.AuthMode == "jwt" && (c.AuthJWKSFile == "") == (c.AuthStaticKey == "") {
+		v.fail("auth_mode", "jwt needs exactly one of auth_jwks_file (AUTH_JWKS_FILE) or auth_static_key (AUTH_STATIC_KEY)")
+	}
+
+	if c.CORSMaxAge < 0 {
+		v.fail("cors_max_age", "must not be negative, got %s", c.CORSMaxAge)
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		v.fail("tls_key_file", "and tls_cert_file (TLS_CERT_FILE) must be set together")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		v.fail("tls_client_ca_file", "needs tls_cert_file (TLS_CERT_FILE), as mutual TLS requires TLS")
+	}
+	v.oneOf("tls_client_auth", c.TLSClientAuth, "require", "optional")
+
+	// Checked even while rate limiting is disabled, so enabling it can't fail
+	if _, err := ratelimit.ParseLimits(c.RateLimits); err != nil {
+		v.fail("rate_limits", "%v", err)
+	}
+	if _, err := ratelimit.ParseLimit(c.RateLimitDefault); err != nil {
+		v.fail("rate_limit_default", "%v", err)
+	}
+
+	if c.LikeQuotaLimit < 0 {
+		v.fail("like_quota_limit", "must not be negative, got %d", c.LikeQuotaLimit)
+	}
+	if c.LikeQuotaLimit > 0 && c.LikeQuotaWindow <= 0 {
+		v.fail("like_quota_window", "must be positive when the quota is enabled, got %s", c.LikeQuotaWindow)
+	}
+
+	if c.LikeExpiry < 0 {
+		v.fail("like_expiry", "must not be negative, got %s", c.LikeExpiry)
+	}
+	if c.LikeExpiry > 0 && c.LikeExpirySweepInterval <= 0 {
+		v.fail("like_expiry_sweep_interval", "must be positive when likes expire, got %s", c.LikeExpirySweepInterval)
+	}
+
+	if c.LikesVisibilityGate && c.VisibilityHandleKey == "" {
+		v.fail("likes_visibility_gate", "needs visibility_handle_key (VISIBILITY_HANDLE_KEY), so liker handles stay stable across replicas and restarts")
+	}
+
+	v.oneOf("trace_exporter", c.TraceExporter, "none", "otlp", "stdout", "file")
+	if c.TraceExporter == "file" && c.TraceFile == "" {
+		v.fail("trace_file", "is required by the file exporter")
+	}
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		v.fail("trace_sample_ratio", "must be between 0 and 1, got %v", c.TraceSampleRatio)
+	}
+
+	if len(v.errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(v.errs...))
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	pgxv5 "github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrSchemaOutOfDate is returned when the database schema does not match
+// the latest migration embedded in the binary
+var ErrSchemaOutOfDate = errors.New("database schema is out of date")
+
+// MigrationStatus describes the schema version applied to a database
+type MigrationStatus struct {
+	Version uint // Currently applied version, 0 if no migrations have run
+	Latest  uint // Latest version embedded in the binary
+	Dirty   bool // True if a previous migration failed part way through
+}
+
+// UpToDate reports whether the database is at the latest clean version
+func (s MigrationStatus) UpToDate() bool {
+	return !s.Dirty && s.Version == s.Latest
+}
+
+// Migrator applies the embedded migrations to a database
+type Migrator struct {
+	m      *migrate.Migrate
+	latest uint
+}
+
+// NewMigrator opens a dedicated connection to the database and prepares the
+// embedded migrations. Close must be called once the migrator is no longer needed.
+func NewMigrator(databaseURL string) (*Migrator, error) {
+	src, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	latest, err := LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	driver, err := pgx.WithInstance(conn, &pgx.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return &Migrator{
+		m:      m,
+		latest: latest,
+	}, nil
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back the given number of migrations
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	if err := m.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// Status returns the applied and latest schema versions
+func (m *Migrator) Status() (MigrationStatus, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return MigrationStatus{}, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return MigrationStatus{
+		Version: version,
+		Latest:  m.latest,
+		Dirty:   dirty,
+	}, nil
+}
+
+// CheckVersion returns ErrSchemaOutOfDate if the database is not at the latest version
+func (m *Migrator) CheckVersion() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	return status.check()
+}
+
+func (s MigrationStatus) check() error {
+	if !s.UpToDate() {
+		return fmt.Errorf("%w: at version %d (dirty=%t), want %d", ErrSchemaOutOfDate, s.Version, s.Dirty, s.Latest)
+	}
+	return nil
+}
+
+// CheckSchemaVersion reads the applied version directly from the schema_migrations
+// table over an existing connection, returning ErrSchemaOutOfDate if it is not the
+// latest embedded version. Unlike Migrator it needs no dedicated connection, so it
+// is cheap enough to call from periodic readiness probes.
+func CheckSchemaVersion(ctx context.Context, conn DBTX) error {
+	latest, err := LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	var version int64
+	var dirty bool
+	err = conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, pgxv5.ErrNoRows) {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return MigrationStatus{
+		Version: uint(version),
+		Latest:  latest,
+		Dirty:   dirty,
+	}.check()
+}
+
+// LatestVersion returns the highest migration version embedded in the binary
+func LatestVersion() (uint, error) {
+	src, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	defer src.Close()
+
+	return latestVersion(src)
+}
+
+// Close releases the migrator's source and database connection
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// latestVersion walks the migration source and returns the highest version
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	// Every up migration must have a matching down migration
+	names := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	for name := range names {
+		if strings.HasSuffix(name, ".up.sql") {
+			assert.True(t, names[strings.TrimSuffix(name, ".up.sql")+".down.sql"], "missing down migration for %s", name)
+		}
+	}
+
+	src, err := iofs.New(migrationFiles, "migrations")
+	require.NoError(t, err)
+
+	latest, err := latestVersion(src)
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(entries)/2), latest)
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_quota_likes_actor;
+DROP TABLE IF EXISTS quota_likes;
@@ -0,0 +1,7 @@
+CREATE TABLE quota_likes (
+                             actor_user_id TEXT NOT NULL,
+                             recipient_user_id TEXT NOT NULL,
+                             created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_quota_likes_actor ON quota_likes (actor_user_id, created_at);
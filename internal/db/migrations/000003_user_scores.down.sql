@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_decisions_updated_at;
+DROP TABLE IF EXISTS user_scores;
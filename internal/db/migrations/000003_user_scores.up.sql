@@ -0,0 +1,9 @@
+CREATE TABLE user_scores (
+                             user_id TEXT PRIMARY KEY,
+                             score DOUBLE PRECISION NOT NULL,
+                             likes_received BIGINT NOT NULL DEFAULT 0,
+                             passes_received BIGINT NOT NULL DEFAULT 0,
+                             updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_decisions_updated_at ON decisions (updated_at, actor_user_id, recipient_user_id);
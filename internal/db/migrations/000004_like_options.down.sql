@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_recipient_super_likes;
+DROP INDEX IF EXISTS idx_recipient_likes;
+CREATE INDEX idx_recipient_likes ON decisions (recipient_user_id, liked, created_at DESC);
+
+ALTER TABLE decisions DROP COLUMN IF EXISTS super_like;
+ALTER TABLE decisions DROP COLUMN IF EXISTS id;
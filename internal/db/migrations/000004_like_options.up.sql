@@ -0,0 +1,7 @@
+ALTER TABLE decisions ADD COLUMN id BIGINT NOT NULL GENERATED ALWAYS AS IDENTITY;
+ALTER TABLE decisions ADD COLUMN super_like BOOLEAN NOT NULL DEFAULT false;
+
+-- Serves liker lists in either order, keyset paginated on (created_at, id)
+DROP INDEX IF EXISTS idx_recipient_likes;
+CREATE INDEX idx_recipient_likes ON decisions (recipient_user_id, created_at, id) WHERE liked = true;
+CREATE INDEX idx_recipient_super_likes ON decisions (recipient_user_id, created_at, id) WHERE liked = true AND super_like = true;
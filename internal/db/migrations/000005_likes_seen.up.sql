@@ -0,0 +1,5 @@
+CREATE TABLE likes_seen (
+                            recipient_user_id TEXT PRIMARY KEY,
+                            seen_before TIMESTAMPTZ NOT NULL,
+                            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
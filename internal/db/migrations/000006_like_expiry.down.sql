@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_likes_created_at;
+DROP TABLE IF EXISTS expired_likes;
@@ -0,0 +1,9 @@
+CREATE TABLE expired_likes (
+                               actor_user_id TEXT NOT NULL,
+                               recipient_user_id TEXT NOT NULL,
+                               liked_at TIMESTAMPTZ NOT NULL,
+                               expired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                               PRIMARY KEY (actor_user_id, recipient_user_id, liked_at)
+);
+
+CREATE INDEX idx_likes_created_at ON decisions (created_at) WHERE liked = true;
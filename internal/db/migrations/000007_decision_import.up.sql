@@ -0,0 +1,19 @@
+-- Tracks how many records of each bulk import have been committed, so an
+-- interrupted import can resume where it stopped
+CREATE TABLE import_checkpoints (
+                                    name TEXT PRIMARY KEY,
+                                    position BIGINT NOT NULL,
+                                    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Keep updated_at when a writer sets it explicitly, so imports can preserve
+-- historical timestamps
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+    RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.updated_at IS NOT DISTINCT FROM OLD.updated_at THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
@@ -0,0 +1 @@
+ALTER TABLE quota_likes DROP CONSTRAINT IF EXISTS quota_likes_pkey;
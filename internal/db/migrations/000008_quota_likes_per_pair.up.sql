@@ -0,0 +1,9 @@
+-- Count each liked user at most once per actor, keeping their latest like
+DELETE FROM quota_likes
+WHERE ctid NOT IN (
+    SELECT DISTINCT ON (actor_user_id, recipient_user_id) ctid
+    FROM quota_likes
+    ORDER BY actor_user_id, recipient_user_id, created_at DESC
+);
+
+ALTER TABLE quota_likes ADD PRIMARY KEY (actor_user_id, recipient_user_id);
@@ -0,0 +1,13 @@
+-- The creation time up to which likes have been checked for expiry, so each
+-- sweep only reads likes that expired since the previous one
+CREATE TABLE like_expiry_sweeps (
+                                    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+                                    swept_before TIMESTAMPTZ NOT NULL,
+                                    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Resume from the latest like already archived
+INSERT INTO like_expiry_sweeps (swept_before)
+SELECT MAX(liked_at)
+FROM expired_likes
+HAVING MAX(liked_at) IS NOT NULL;
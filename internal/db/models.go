@@ -14,4 +14,45 @@ type Decision struct {
 	Liked           bool      `json:"liked"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
+	ID              int64     `json:"id"`
+	SuperLike       bool      `json:"superLike"`
+}
+
+type ExpiredLike struct {
+	ActorUserID     string    `json:"actorUserId"`
+	RecipientUserID string    `json:"recipientUserId"`
+	LikedAt         time.Time `json:"likedAt"`
+	ExpiredAt       time.Time `json:"expiredAt"`
+}
+
+type ImportCheckpoint struct {
+	Name      string    `json:"name"`
+	Position  int64     `json:"position"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type LikeExpirySweep struct {
+	ID          bool      `json:"id"`
+	SweptBefore time.Time `json:"sweptBefore"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type LikesSeen struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	SeenBefore      time.Time `json:"seenBefore"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type QuotaLike struct {
+	ActorUserID     string    `json:"actorUserId"`
+	RecipientUserID string    `json:"recipientUserId"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+type UserScore struct {
+	UserID         string    `json:"userId"`
+	Score          float64   `json:"score"`
+	LikesReceived  int64     `json:"likesReceived"`
+	PassesReceived int64     `json:"passesReceived"`
+	UpdatedAt      time.Time `json:"updatedAt"`
 }
@@ -6,13 +6,36 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	AdvanceLikeExpirySweep(ctx context.Context, sweptBefore time.Time) error
+	ArchiveExpiredLikes(ctx context.Context, arg ArchiveExpiredLikesParams) ([]ExpiredLike, error)
+	CountFilteredLikers(ctx context.Context, arg CountFilteredLikersParams) (int64, error)
 	CountLikers(ctx context.Context, recipientUserID string) (int64, error)
+	CountNewLikers(ctx context.Context, arg CountNewLikersParams) (int64, error)
+	CountUnseenLikers(ctx context.Context, arg CountUnseenLikersParams) (int64, error)
+	GetDecision(ctx context.Context, arg GetDecisionParams) (bool, error)
+	GetImportCheckpoint(ctx context.Context, name string) (int64, error)
+	GetQuotaUsage(ctx context.Context, arg GetQuotaUsageParams) (GetQuotaUsageRow, error)
+	GetUserScore(ctx context.Context, userID string) (UserScore, error)
+	IsQuotaLikeCounted(ctx context.Context, arg IsQuotaLikeCountedParams) (bool, error)
+	ListDecidedRecipients(ctx context.Context, arg ListDecidedRecipientsParams) ([]string, error)
+	ListDecisionsAfter(ctx context.Context, arg ListDecisionsAfterParams) ([]ListDecisionsAfterRow, error)
 	ListLikers(ctx context.Context, arg ListLikersParams) ([]ListLikersRow, error)
+	ListLikersAmong(ctx context.Context, arg ListLikersAmongParams) ([]string, error)
+	ListLikersOldestFirst(ctx context.Context, arg ListLikersOldestFirstParams) ([]ListLikersOldestFirstRow, error)
 	ListNewLikers(ctx context.Context, arg ListNewLikersParams) ([]ListNewLikersRow, error)
+	ListNewLikersOldestFirst(ctx context.Context, arg ListNewLikersOldestFirstParams) ([]ListNewLikersOldestFirstRow, error)
+	LockActorDecisions(ctx context.Context, actorUserID string) error
+	LockUserScore(ctx context.Context, arg LockUserScoreParams) (UserScore, error)
+	MarkLikesSeen(ctx context.Context, arg MarkLikesSeenParams) (time.Time, error)
 	PutDecision(ctx context.Context, arg PutDecisionParams) (bool, error)
+	RecordQuotaLike(ctx context.Context, arg RecordQuotaLikeParams) error
+	RewindLikeExpirySweep(ctx context.Context, sweptBefore time.Time) error
+	SaveImportCheckpoint(ctx context.Context, arg SaveImportCheckpointParams) error
+	SetUserScore(ctx context.Context, arg SetUserScoreParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -1,11 +1,11 @@
 -- name: PutDecision :one
 INSERT INTO decisions (
-    actor_user_id, recipient_user_id, liked
+    actor_user_id, recipient_user_id, liked, super_like
 ) VALUES (
-             $1, $2, $3
+             $1, $2, $3, $4
          )
 ON CONFLICT (actor_user_id, recipient_user_id)
-    DO UPDATE SET liked = EXCLUDED.liked, updated_at = NOW()
+    DO UPDATE SET liked = EXCLUDED.liked, super_like = EXCLUDED.super_like, updated_at = NOW()
 RETURNING (
     EXISTS (
         SELECT 1 FROM decisions
@@ -18,44 +18,336 @@ RETURNING (
 -- name: ListLikers :many
 SELECT
     actor_user_id,
-    created_at
+    created_at,
+    id,
+    super_like,
+    COALESCE(created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
 FROM decisions
-WHERE recipient_user_id = sqlc.arg(recipient_user_id)
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = decisions.recipient_user_id
+WHERE decisions.recipient_user_id = sqlc.arg(recipient_user_id)
+  AND liked = true
+  AND (sqlc.arg(include_regular)::BOOLEAN OR super_like)
+  AND (sqlc.arg(include_super)::BOOLEAN OR NOT super_like)
+  AND created_at >= sqlc.arg(since)
+  AND (
+    CASE
+        WHEN sqlc.arg(until)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            created_at < sqlc.arg(until)
+        ELSE true
+        END
+    )
+  AND (
+    CASE
+        WHEN sqlc.arg(created_at_cursor)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            (created_at, id) < (sqlc.arg(created_at_cursor), sqlc.arg(id_cursor)::BIGINT)
+        ELSE true
+        END
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListLikersOldestFirst :many
+SELECT
+    actor_user_id,
+    created_at,
+    id,
+    super_like,
+    COALESCE(created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
+FROM decisions
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = decisions.recipient_user_id
+WHERE decisions.recipient_user_id = sqlc.arg(recipient_user_id)
   AND liked = true
+  AND (sqlc.arg(include_regular)::BOOLEAN OR super_like)
+  AND (sqlc.arg(include_super)::BOOLEAN OR NOT super_like)
+  AND created_at >= sqlc.arg(since)
+  AND (
+    CASE
+        WHEN sqlc.arg(until)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            created_at < sqlc.arg(until)
+        ELSE true
+        END
+    )
   AND (
     CASE
         WHEN sqlc.arg(created_at_cursor)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
-            created_at < sqlc.arg(created_at_cursor)
+            (created_at, id) > (sqlc.arg(created_at_cursor), sqlc.arg(id_cursor)::BIGINT)
         ELSE true
         END
     )
-ORDER BY created_at DESC, actor_user_id DESC
+ORDER BY created_at ASC, id ASC
 LIMIT sqlc.arg(page_limit);
 
 -- name: ListNewLikers :many
 SELECT
     d1.actor_user_id,
-    d1.created_at
+    d1.created_at,
+    d1.id,
+    d1.super_like,
+    COALESCE(d1.created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
 FROM decisions d1
          LEFT JOIN decisions d2 ON
     d1.actor_user_id = d2.recipient_user_id
         AND d1.recipient_user_id = d2.actor_user_id
         AND d2.liked = true
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = d1.recipient_user_id
 WHERE d1.recipient_user_id = sqlc.arg(recipient_user_id)
   AND d1.liked = true
   AND d2.actor_user_id IS NULL
+  AND (sqlc.arg(include_regular)::BOOLEAN OR d1.super_like)
+  AND (sqlc.arg(include_super)::BOOLEAN OR NOT d1.super_like)
+  AND d1.created_at >= sqlc.arg(since)
+  AND (
+    CASE
+        WHEN sqlc.arg(until)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            d1.created_at < sqlc.arg(until)
+        ELSE true
+        END
+    )
   AND (
     CASE
         WHEN sqlc.arg(created_at_cursor)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
-            d1.created_at < sqlc.arg(created_at_cursor)
+            (d1.created_at, d1.id) < (sqlc.arg(created_at_cursor), sqlc.arg(id_cursor)::BIGINT)
         ELSE true
         END
     )
-ORDER BY d1.created_at DESC, d1.actor_user_id DESC
+ORDER BY d1.created_at DESC, d1.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListNewLikersOldestFirst :many
+SELECT
+    d1.actor_user_id,
+    d1.created_at,
+    d1.id,
+    d1.super_like,
+    COALESCE(d1.created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = d1.recipient_user_id
+WHERE d1.recipient_user_id = sqlc.arg(recipient_user_id)
+  AND d1.liked = true
+  AND d2.actor_user_id IS NULL
+  AND (sqlc.arg(include_regular)::BOOLEAN OR d1.super_like)
+  AND (sqlc.arg(include_super)::BOOLEAN OR NOT d1.super_like)
+  AND d1.created_at >= sqlc.arg(since)
+  AND (
+    CASE
+        WHEN sqlc.arg(until)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            d1.created_at < sqlc.arg(until)
+        ELSE true
+        END
+    )
+  AND (
+    CASE
+        WHEN sqlc.arg(created_at_cursor)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            (d1.created_at, d1.id) > (sqlc.arg(created_at_cursor), sqlc.arg(id_cursor)::BIGINT)
+        ELSE true
+        END
+    )
+ORDER BY d1.created_at ASC, d1.id ASC
 LIMIT sqlc.arg(page_limit);
 
 -- name: CountLikers :one
 SELECT COUNT(*)
 FROM decisions
 WHERE recipient_user_id = $1
-  AND liked = true;
\ No newline at end of file
+  AND liked = true;
+
+-- name: GetDecision :one
+SELECT liked
+FROM decisions
+WHERE actor_user_id = $1
+  AND recipient_user_id = $2;
+
+-- name: GetQuotaUsage :one
+SELECT
+    COUNT(*) AS used,
+    COALESCE(MIN(created_at), NOW())::TIMESTAMPTZ AS oldest
+FROM quota_likes
+WHERE actor_user_id = sqlc.arg(actor_user_id)
+  AND created_at > sqlc.arg(window_start);
+
+-- name: IsQuotaLikeCounted :one
+SELECT EXISTS (
+    SELECT 1
+    FROM quota_likes
+    WHERE actor_user_id = sqlc.arg(actor_user_id)
+      AND recipient_user_id = sqlc.arg(recipient_user_id)
+      AND created_at > sqlc.arg(window_start)
+) counted;
+
+-- name: RecordQuotaLike :exec
+WITH pruned AS (
+    DELETE FROM quota_likes
+    WHERE quota_likes.actor_user_id = sqlc.arg(actor_user_id)
+      AND quota_likes.recipient_user_id <> sqlc.arg(recipient_user_id)
+      AND quota_likes.created_at <= sqlc.arg(window_start)
+)
+INSERT INTO quota_likes (
+    actor_user_id, recipient_user_id
+) VALUES (
+             sqlc.arg(actor_user_id), sqlc.arg(recipient_user_id)
+         )
+ON CONFLICT (actor_user_id, recipient_user_id)
+    DO UPDATE SET created_at = NOW();
+
+-- name: LockActorDecisions :exec
+SELECT pg_advisory_xact_lock(hashtextextended('decisions:' || sqlc.arg(actor_user_id)::TEXT, 0));
+
+-- name: CountFilteredLikers :one
+SELECT COUNT(*)
+FROM decisions
+WHERE recipient_user_id = sqlc.arg(recipient_user_id)
+  AND liked = true
+  AND (sqlc.arg(include_regular)::BOOLEAN OR super_like)
+  AND (sqlc.arg(include_super)::BOOLEAN OR NOT super_like)
+  AND created_at >= sqlc.arg(since)
+  AND (
+    CASE
+        WHEN sqlc.arg(until)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            created_at < sqlc.arg(until)
+        ELSE true
+        END
+    );
+
+-- name: CountNewLikers :one
+SELECT COUNT(*)
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+WHERE d1.recipient_user_id = sqlc.arg(recipient_user_id)
+  AND d1.liked = true
+  AND d2.actor_user_id IS NULL
+  AND (sqlc.arg(include_regular)::BOOLEAN OR d1.super_like)
+  AND (sqlc.arg(include_super)::BOOLEAN OR NOT d1.super_like)
+  AND d1.created_at >= sqlc.arg(since)
+  AND (
+    CASE
+        WHEN sqlc.arg(until)::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            d1.created_at < sqlc.arg(until)
+        ELSE true
+        END
+    );
+
+-- name: ListDecidedRecipients :many
+SELECT recipient_user_id
+FROM decisions
+WHERE actor_user_id = sqlc.arg(actor_user_id)
+  AND recipient_user_id = ANY(sqlc.arg(candidate_user_ids)::TEXT[]);
+
+-- name: ListLikersAmong :many
+SELECT actor_user_id
+FROM decisions
+WHERE recipient_user_id = sqlc.arg(recipient_user_id)
+  AND liked = true
+  AND actor_user_id = ANY(sqlc.arg(candidate_user_ids)::TEXT[]);
+
+-- name: GetUserScore :one
+SELECT user_id, score, likes_received, passes_received, updated_at
+FROM user_scores
+WHERE user_id = $1;
+
+-- name: LockUserScore :one
+INSERT INTO user_scores (
+    user_id, score
+) VALUES (
+             sqlc.arg(user_id), sqlc.arg(initial_score)
+         )
+ON CONFLICT (user_id)
+    DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING user_id, score, likes_received, passes_received, updated_at;
+
+-- name: SetUserScore :exec
+INSERT INTO user_scores (
+    user_id, score, likes_received, passes_received
+) VALUES (
+             $1, $2, $3, $4
+         )
+ON CONFLICT (user_id)
+    DO UPDATE SET score = EXCLUDED.score,
+                  likes_received = EXCLUDED.likes_received,
+                  passes_received = EXCLUDED.passes_received,
+                  updated_at = NOW();
+
+-- name: ListDecisionsAfter :many
+SELECT actor_user_id, recipient_user_id, liked, updated_at
+FROM decisions
+WHERE (updated_at, actor_user_id, recipient_user_id) > (sqlc.arg(updated_at_cursor)::TIMESTAMPTZ, sqlc.arg(actor_user_id_cursor)::TEXT, sqlc.arg(recipient_user_id_cursor)::TEXT)
+ORDER BY updated_at, actor_user_id, recipient_user_id
+LIMIT sqlc.arg(page_limit);
+
+-- name: MarkLikesSeen :one
+INSERT INTO likes_seen (
+    recipient_user_id, seen_before
+) VALUES (
+             $1, $2
+         )
+ON CONFLICT (recipient_user_id)
+    DO UPDATE SET seen_before = GREATEST(likes_seen.seen_before, EXCLUDED.seen_before), updated_at = NOW()
+RETURNING seen_before;
+
+-- name: CountUnseenLikers :one
+SELECT COUNT(*)
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = d1.recipient_user_id
+WHERE d1.recipient_user_id = sqlc.arg(recipient_user_id)
+  AND d1.liked = true
+  AND (likes_seen.seen_before IS NULL OR d1.created_at >= likes_seen.seen_before)
+  AND (d1.created_at >= sqlc.arg(expired_before) OR d2.actor_user_id IS NOT NULL);
+
+-- name: ArchiveExpiredLikes :many
+INSERT INTO expired_likes (
+    actor_user_id, recipient_user_id, liked_at
+)
+SELECT d1.actor_user_id, d1.recipient_user_id, d1.created_at
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+         LEFT JOIN expired_likes e ON
+    e.actor_user_id = d1.actor_user_id
+        AND e.recipient_user_id = d1.recipient_user_id
+        AND e.liked_at = d1.created_at
+WHERE d1.liked = true
+  AND d2.actor_user_id IS NULL
+  AND e.actor_user_id IS NULL
+  AND (d1.created_at >= COALESCE((SELECT swept_before FROM like_expiry_sweeps), '-infinity'::TIMESTAMPTZ)
+    OR d1.updated_at >= COALESCE((SELECT swept_before FROM like_expiry_sweeps), '-infinity'::TIMESTAMPTZ))
+  AND d1.created_at < sqlc.arg(expired_before)
+ORDER BY d1.created_at
+LIMIT sqlc.arg(batch_size)
+ON CONFLICT DO NOTHING
+RETURNING actor_user_id, recipient_user_id, liked_at, expired_at;
+
+-- name: AdvanceLikeExpirySweep :exec
+INSERT INTO like_expiry_sweeps (
+    swept_before
+) VALUES (
+             sqlc.arg(swept_before)
+         )
+ON CONFLICT (id)
+    DO UPDATE SET swept_before = GREATEST(like_expiry_sweeps.swept_before, EXCLUDED.swept_before), updated_at = NOW();
+
+-- name: RewindLikeExpirySweep :exec
+UPDATE like_expiry_sweeps
+SET swept_before = LEAST(swept_before, sqlc.arg(swept_before)), updated_at = NOW();
+
+-- name: GetImportCheckpoint :one
+SELECT position
+FROM import_checkpoints
+WHERE name = $1;
+
+-- name: SaveImportCheckpoint :exec
+INSERT INTO import_checkpoints (name, position)
+VALUES ($1, $2)
+ON CONFLICT (name)
+    DO UPDATE SET position = EXCLUDED.position, updated_at = NOW();
@@ -10,6 +10,115 @@ import (
 	"time"
 )
 
+const advanceLikeExpirySweep = `-- name: AdvanceLikeExpirySweep :exec
+INSERT INTO like_expiry_sweeps (
+    swept_before
+) VALUES (
+             $1
+         )
+ON CONFLICT (id)
+    DO UPDATE SET swept_before = GREATEST(like_expiry_sweeps.swept_before, EXCLUDED.swept_before), updated_at = NOW()
+`
+
+func (q *Queries) AdvanceLikeExpirySweep(ctx context.Context, sweptBefore time.Time) error {
+	_, err := q.db.Exec(ctx, advanceLikeExpirySweep, sweptBefore)
+	return err
+}
+
+const archiveExpiredLikes = `-- name: ArchiveExpiredLikes :many
+INSERT INTO expired_likes (
+    actor_user_id, recipient_user_id, liked_at
+)
+SELECT d1.actor_user_id, d1.recipient_user_id, d1.created_at
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+         LEFT JOIN expired_likes e ON
+    e.actor_user_id = d1.actor_user_id
+        AND e.recipient_user_id = d1.recipient_user_id
+        AND e.liked_at = d1.created_at
+WHERE d1.liked = true
+  AND d2.actor_user_id IS NULL
+  AND e.actor_user_id IS NULL
+  AND (d1.created_at >= COALESCE((SELECT swept_before FROM like_expiry_sweeps), '-infinity'::TIMESTAMPTZ)
+    OR d1.updated_at >= COALESCE((SELECT swept_before FROM like_expiry_sweeps), '-infinity'::TIMESTAMPTZ))
+  AND d1.created_at < $1
+ORDER BY d1.created_at
+LIMIT $2
+ON CONFLICT DO NOTHING
+RETURNING actor_user_id, recipient_user_id, liked_at, expired_at
+`
+
+type ArchiveExpiredLikesParams struct {
+	ExpiredBefore time.Time `json:"expiredBefore"`
+	BatchSize     int32     `json:"batchSize"`
+}
+
+func (q *Queries) ArchiveExpiredLikes(ctx context.Context, arg ArchiveExpiredLikesParams) ([]ExpiredLike, error) {
+	rows, err := q.db.Query(ctx, archiveExpiredLikes, arg.ExpiredBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpiredLike
+	for rows.Next() {
+		var i ExpiredLike
+		if err := rows.Scan(
+			&i.ActorUserID,
+			&i.RecipientUserID,
+			&i.LikedAt,
+			&i.ExpiredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countFilteredLikers = `-- name: CountFilteredLikers :one
+SELECT COUNT(*)
+FROM decisions
+WHERE recipient_user_id = $1
+  AND liked = true
+  AND ($2::BOOLEAN OR super_like)
+  AND ($3::BOOLEAN OR NOT super_like)
+  AND created_at >= $4
+  AND (
+    CASE
+        WHEN $5::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            created_at < $5
+        ELSE true
+        END
+    )
+`
+
+type CountFilteredLikersParams struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	IncludeRegular  bool      `json:"includeRegular"`
+	IncludeSuper    bool      `json:"includeSuper"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
+}
+
+func (q *Queries) CountFilteredLikers(ctx context.Context, arg CountFilteredLikersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countFilteredLikers,
+		arg.RecipientUserID,
+		arg.IncludeRegular,
+		arg.IncludeSuper,
+		arg.Since,
+		arg.Until,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countLikers = `-- name: CountLikers :one
 SELECT COUNT(*)
 FROM decisions
@@ -24,37 +133,321 @@ func (q *Queries) CountLikers(ctx context.Context, recipientUserID string) (int6
 	return count, err
 }
 
+const countNewLikers = `-- name: CountNewLikers :one
+SELECT COUNT(*)
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+WHERE d1.recipient_user_id = $1
+  AND d1.liked = true
+  AND d2.actor_user_id IS NULL
+  AND ($2::BOOLEAN OR d1.super_like)
+  AND ($3::BOOLEAN OR NOT d1.super_like)
+  AND d1.created_at >= $4
+  AND (
+    CASE
+        WHEN $5::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            d1.created_at < $5
+        ELSE true
+        END
+    )
+`
+
+type CountNewLikersParams struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	IncludeRegular  bool      `json:"includeRegular"`
+	IncludeSuper    bool      `json:"includeSuper"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
+}
+
+func (q *Queries) CountNewLikers(ctx context.Context, arg CountNewLikersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countNewLikers,
+		arg.RecipientUserID,
+		arg.IncludeRegular,
+		arg.IncludeSuper,
+		arg.Since,
+		arg.Until,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUnseenLikers = `-- name: CountUnseenLikers :one
+SELECT COUNT(*)
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = d1.recipient_user_id
+WHERE d1.recipient_user_id = $1
+  AND d1.liked = true
+  AND (likes_seen.seen_before IS NULL OR d1.created_at >= likes_seen.seen_before)
+  AND (d1.created_at >= $2 OR d2.actor_user_id IS NOT NULL)
+`
+
+type CountUnseenLikersParams struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	ExpiredBefore   time.Time `json:"expiredBefore"`
+}
+
+func (q *Queries) CountUnseenLikers(ctx context.Context, arg CountUnseenLikersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnseenLikers, arg.RecipientUserID, arg.ExpiredBefore)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getDecision = `-- name: GetDecision :one
+SELECT liked
+FROM decisions
+WHERE actor_user_id = $1
+  AND recipient_user_id = $2
+`
+
+type GetDecisionParams struct {
+	ActorUserID     string `json:"actorUserId"`
+	RecipientUserID string `json:"recipientUserId"`
+}
+
+func (q *Queries) GetDecision(ctx context.Context, arg GetDecisionParams) (bool, error) {
+	row := q.db.QueryRow(ctx, getDecision, arg.ActorUserID, arg.RecipientUserID)
+	var liked bool
+	err := row.Scan(&liked)
+	return liked, err
+}
+
+const getImportCheckpoint = `-- name: GetImportCheckpoint :one
+SELECT position
+FROM import_checkpoints
+WHERE name = $1
+`
+
+func (q *Queries) GetImportCheckpoint(ctx context.Context, name string) (int64, error) {
+	row := q.db.QueryRow(ctx, getImportCheckpoint, name)
+	var position int64
+	err := row.Scan(&position)
+	return position, err
+}
+
+const getQuotaUsage = `-- name: GetQuotaUsage :one
+SELECT
+    COUNT(*) AS used,
+    COALESCE(MIN(created_at), NOW())::TIMESTAMPTZ AS oldest
+FROM quota_likes
+WHERE actor_user_id = $1
+  AND created_at > $2
+`
+
+type GetQuotaUsageParams struct {
+	ActorUserID string    `json:"actorUserId"`
+	WindowStart time.Time `json:"windowStart"`
+}
+
+type GetQuotaUsageRow struct {
+	Used   int64     `json:"used"`
+	Oldest time.Time `json:"oldest"`
+}
+
+func (q *Queries) GetQuotaUsage(ctx context.Context, arg GetQuotaUsageParams) (GetQuotaUsageRow, error) {
+	row := q.db.QueryRow(ctx, getQuotaUsage, arg.ActorUserID, arg.WindowStart)
+	var i GetQuotaUsageRow
+	err := row.Scan(&i.Used, &i.Oldest)
+	return i, err
+}
+
+const getUserScore = `-- name: GetUserScore :one
+SELECT user_id, score, likes_received, passes_received, updated_at
+FROM user_scores
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserScore(ctx context.Context, userID string) (UserScore, error) {
+	row := q.db.QueryRow(ctx, getUserScore, userID)
+	var i UserScore
+	err := row.Scan(
+		&i.UserID,
+		&i.Score,
+		&i.LikesReceived,
+		&i.PassesReceived,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isQuotaLikeCounted = `-- name: IsQuotaLikeCounted :one
+SELECT EXISTS (
+    SELECT 1
+    FROM quota_likes
+    WHERE actor_user_id = $1
+      AND recipient_user_id = $2
+      AND created_at > $3
+) counted
+`
+
+type IsQuotaLikeCountedParams struct {
+	ActorUserID     string    `json:"actorUserId"`
+	RecipientUserID string    `json:"recipientUserId"`
+	WindowStart     time.Time `json:"windowStart"`
+}
+
+func (q *Queries) IsQuotaLikeCounted(ctx context.Context, arg IsQuotaLikeCountedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isQuotaLikeCounted, arg.ActorUserID, arg.RecipientUserID, arg.WindowStart)
+	var counted bool
+	err := row.Scan(&counted)
+	return counted, err
+}
+
+const listDecidedRecipients = `-- name: ListDecidedRecipients :many
+SELECT recipient_user_id
+FROM decisions
+WHERE actor_user_id = $1
+  AND recipient_user_id = ANY($2::TEXT[])
+`
+
+type ListDecidedRecipientsParams struct {
+	ActorUserID      string   `json:"actorUserId"`
+	CandidateUserIds []string `json:"candidateUserIds"`
+}
+
+func (q *Queries) ListDecidedRecipients(ctx context.Context, arg ListDecidedRecipientsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listDecidedRecipients, arg.ActorUserID, arg.CandidateUserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var recipient_user_id string
+		if err := rows.Scan(&recipient_user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, recipient_user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDecisionsAfter = `-- name: ListDecisionsAfter :many
+SELECT actor_user_id, recipient_user_id, liked, updated_at
+FROM decisions
+WHERE (updated_at, actor_user_id, recipient_user_id) > ($1::TIMESTAMPTZ, $2::TEXT, $3::TEXT)
+ORDER BY updated_at, actor_user_id, recipient_user_id
+LIMIT $4
+`
+
+type ListDecisionsAfterParams struct {
+	UpdatedAtCursor       time.Time `json:"updatedAtCursor"`
+	ActorUserIDCursor     string    `json:"actorUserIdCursor"`
+	RecipientUserIDCursor string    `json:"recipientUserIdCursor"`
+	PageLimit             int32     `json:"pageLimit"`
+}
+
+type ListDecisionsAfterRow struct {
+	ActorUserID     string    `json:"actorUserId"`
+	RecipientUserID string    `json:"recipientUserId"`
+	Liked           bool      `json:"liked"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+func (q *Queries) ListDecisionsAfter(ctx context.Context, arg ListDecisionsAfterParams) ([]ListDecisionsAfterRow, error) {
+	rows, err := q.db.Query(ctx, listDecisionsAfter,
+		arg.UpdatedAtCursor,
+		arg.ActorUserIDCursor,
+		arg.RecipientUserIDCursor,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDecisionsAfterRow
+	for rows.Next() {
+		var i ListDecisionsAfterRow
+		if err := rows.Scan(
+			&i.ActorUserID,
+			&i.RecipientUserID,
+			&i.Liked,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLikers = `-- name: ListLikers :many
 SELECT
     actor_user_id,
-    created_at
+    created_at,
+    id,
+    super_like,
+    COALESCE(created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
 FROM decisions
-WHERE recipient_user_id = $1
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = decisions.recipient_user_id
+WHERE decisions.recipient_user_id = $1
   AND liked = true
+  AND ($2::BOOLEAN OR super_like)
+  AND ($3::BOOLEAN OR NOT super_like)
+  AND created_at >= $4
+  AND (
+    CASE
+        WHEN $5::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            created_at < $5
+        ELSE true
+        END
+    )
   AND (
     CASE
-        WHEN $2::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
-            created_at < $2
+        WHEN $6::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            (created_at, id) < ($6, $7::BIGINT)
         ELSE true
         END
     )
-ORDER BY created_at DESC, actor_user_id DESC
-LIMIT $3
+ORDER BY created_at DESC, id DESC
+LIMIT $8
 `
 
 type ListLikersParams struct {
 	RecipientUserID string    `json:"recipientUserId"`
+	IncludeRegular  bool      `json:"includeRegular"`
+	IncludeSuper    bool      `json:"includeSuper"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
 	CreatedAtCursor time.Time `json:"createdAtCursor"`
+	IDCursor        int64     `json:"idCursor"`
 	PageLimit       int32     `json:"pageLimit"`
 }
 
 type ListLikersRow struct {
 	ActorUserID string    `json:"actorUserId"`
 	CreatedAt   time.Time `json:"createdAt"`
+	ID          int64     `json:"id"`
+	SuperLike   bool      `json:"superLike"`
+	Seen        bool      `json:"seen"`
 }
 
 func (q *Queries) ListLikers(ctx context.Context, arg ListLikersParams) ([]ListLikersRow, error) {
-	rows, err := q.db.Query(ctx, listLikers, arg.RecipientUserID, arg.CreatedAtCursor, arg.PageLimit)
+	rows, err := q.db.Query(ctx, listLikers,
+		arg.RecipientUserID,
+		arg.IncludeRegular,
+		arg.IncludeSuper,
+		arg.Since,
+		arg.Until,
+		arg.CreatedAtCursor,
+		arg.IDCursor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +455,132 @@ func (q *Queries) ListLikers(ctx context.Context, arg ListLikersParams) ([]ListL
 	var items []ListLikersRow
 	for rows.Next() {
 		var i ListLikersRow
-		if err := rows.Scan(&i.ActorUserID, &i.CreatedAt); err != nil {
+		if err := rows.Scan(
+			&i.ActorUserID,
+			&i.CreatedAt,
+			&i.ID,
+			&i.SuperLike,
+			&i.Seen,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLikersAmong = `-- name: ListLikersAmong :many
+SELECT actor_user_id
+FROM decisions
+WHERE recipient_user_id = $1
+  AND liked = true
+  AND actor_user_id = ANY($2::TEXT[])
+`
+
+type ListLikersAmongParams struct {
+	RecipientUserID  string   `json:"recipientUserId"`
+	CandidateUserIds []string `json:"candidateUserIds"`
+}
+
+func (q *Queries) ListLikersAmong(ctx context.Context, arg ListLikersAmongParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listLikersAmong, arg.RecipientUserID, arg.CandidateUserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var actor_user_id string
+		if err := rows.Scan(&actor_user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, actor_user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLikersOldestFirst = `-- name: ListLikersOldestFirst :many
+SELECT
+    actor_user_id,
+    created_at,
+    id,
+    super_like,
+    COALESCE(created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
+FROM decisions
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = decisions.recipient_user_id
+WHERE decisions.recipient_user_id = $1
+  AND liked = true
+  AND ($2::BOOLEAN OR super_like)
+  AND ($3::BOOLEAN OR NOT super_like)
+  AND created_at >= $4
+  AND (
+    CASE
+        WHEN $5::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            created_at < $5
+        ELSE true
+        END
+    )
+  AND (
+    CASE
+        WHEN $6::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            (created_at, id) > ($6, $7::BIGINT)
+        ELSE true
+        END
+    )
+ORDER BY created_at ASC, id ASC
+LIMIT $8
+`
+
+type ListLikersOldestFirstParams struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	IncludeRegular  bool      `json:"includeRegular"`
+	IncludeSuper    bool      `json:"includeSuper"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
+	CreatedAtCursor time.Time `json:"createdAtCursor"`
+	IDCursor        int64     `json:"idCursor"`
+	PageLimit       int32     `json:"pageLimit"`
+}
+
+type ListLikersOldestFirstRow struct {
+	ActorUserID string    `json:"actorUserId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ID          int64     `json:"id"`
+	SuperLike   bool      `json:"superLike"`
+	Seen        bool      `json:"seen"`
+}
+
+func (q *Queries) ListLikersOldestFirst(ctx context.Context, arg ListLikersOldestFirstParams) ([]ListLikersOldestFirstRow, error) {
+	rows, err := q.db.Query(ctx, listLikersOldestFirst,
+		arg.RecipientUserID,
+		arg.IncludeRegular,
+		arg.IncludeSuper,
+		arg.Since,
+		arg.Until,
+		arg.CreatedAtCursor,
+		arg.IDCursor,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLikersOldestFirstRow
+	for rows.Next() {
+		var i ListLikersOldestFirstRow
+		if err := rows.Scan(
+			&i.ActorUserID,
+			&i.CreatedAt,
+			&i.ID,
+			&i.SuperLike,
+			&i.Seen,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -76,39 +594,70 @@ func (q *Queries) ListLikers(ctx context.Context, arg ListLikersParams) ([]ListL
 const listNewLikers = `-- name: ListNewLikers :many
 SELECT
     d1.actor_user_id,
-    d1.created_at
+    d1.created_at,
+    d1.id,
+    d1.super_like,
+    COALESCE(d1.created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
 FROM decisions d1
          LEFT JOIN decisions d2 ON
     d1.actor_user_id = d2.recipient_user_id
         AND d1.recipient_user_id = d2.actor_user_id
         AND d2.liked = true
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = d1.recipient_user_id
 WHERE d1.recipient_user_id = $1
   AND d1.liked = true
   AND d2.actor_user_id IS NULL
+  AND ($2::BOOLEAN OR d1.super_like)
+  AND ($3::BOOLEAN OR NOT d1.super_like)
+  AND d1.created_at >= $4
   AND (
     CASE
-        WHEN $2::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
-            d1.created_at < $2
+        WHEN $5::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            d1.created_at < $5
         ELSE true
         END
     )
-ORDER BY d1.created_at DESC, d1.actor_user_id DESC
-LIMIT $3
+  AND (
+    CASE
+        WHEN $6::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            (d1.created_at, d1.id) < ($6, $7::BIGINT)
+        ELSE true
+        END
+    )
+ORDER BY d1.created_at DESC, d1.id DESC
+LIMIT $8
 `
 
 type ListNewLikersParams struct {
 	RecipientUserID string    `json:"recipientUserId"`
+	IncludeRegular  bool      `json:"includeRegular"`
+	IncludeSuper    bool      `json:"includeSuper"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
 	CreatedAtCursor time.Time `json:"createdAtCursor"`
+	IDCursor        int64     `json:"idCursor"`
 	PageLimit       int32     `json:"pageLimit"`
 }
 
 type ListNewLikersRow struct {
 	ActorUserID string    `json:"actorUserId"`
 	CreatedAt   time.Time `json:"createdAt"`
+	ID          int64     `json:"id"`
+	SuperLike   bool      `json:"superLike"`
+	Seen        bool      `json:"seen"`
 }
 
 func (q *Queries) ListNewLikers(ctx context.Context, arg ListNewLikersParams) ([]ListNewLikersRow, error) {
-	rows, err := q.db.Query(ctx, listNewLikers, arg.RecipientUserID, arg.CreatedAtCursor, arg.PageLimit)
+	rows, err := q.db.Query(ctx, listNewLikers,
+		arg.RecipientUserID,
+		arg.IncludeRegular,
+		arg.IncludeSuper,
+		arg.Since,
+		arg.Until,
+		arg.CreatedAtCursor,
+		arg.IDCursor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +665,104 @@ func (q *Queries) ListNewLikers(ctx context.Context, arg ListNewLikersParams) ([
 	var items []ListNewLikersRow
 	for rows.Next() {
 		var i ListNewLikersRow
-		if err := rows.Scan(&i.ActorUserID, &i.CreatedAt); err != nil {
+		if err := rows.Scan(
+			&i.ActorUserID,
+			&i.CreatedAt,
+			&i.ID,
+			&i.SuperLike,
+			&i.Seen,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNewLikersOldestFirst = `-- name: ListNewLikersOldestFirst :many
+SELECT
+    d1.actor_user_id,
+    d1.created_at,
+    d1.id,
+    d1.super_like,
+    COALESCE(d1.created_at < likes_seen.seen_before, false)::BOOLEAN AS seen
+FROM decisions d1
+         LEFT JOIN decisions d2 ON
+    d1.actor_user_id = d2.recipient_user_id
+        AND d1.recipient_user_id = d2.actor_user_id
+        AND d2.liked = true
+         LEFT JOIN likes_seen ON likes_seen.recipient_user_id = d1.recipient_user_id
+WHERE d1.recipient_user_id = $1
+  AND d1.liked = true
+  AND d2.actor_user_id IS NULL
+  AND ($2::BOOLEAN OR d1.super_like)
+  AND ($3::BOOLEAN OR NOT d1.super_like)
+  AND d1.created_at >= $4
+  AND (
+    CASE
+        WHEN $5::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            d1.created_at < $5
+        ELSE true
+        END
+    )
+  AND (
+    CASE
+        WHEN $6::TIMESTAMPTZ > '0001-01-02'::TIMESTAMPTZ THEN
+            (d1.created_at, d1.id) > ($6, $7::BIGINT)
+        ELSE true
+        END
+    )
+ORDER BY d1.created_at ASC, d1.id ASC
+LIMIT $8
+`
+
+type ListNewLikersOldestFirstParams struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	IncludeRegular  bool      `json:"includeRegular"`
+	IncludeSuper    bool      `json:"includeSuper"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
+	CreatedAtCursor time.Time `json:"createdAtCursor"`
+	IDCursor        int64     `json:"idCursor"`
+	PageLimit       int32     `json:"pageLimit"`
+}
+
+type ListNewLikersOldestFirstRow struct {
+	ActorUserID string    `json:"actorUserId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ID          int64     `json:"id"`
+	SuperLike   bool      `json:"superLike"`
+	Seen        bool      `json:"seen"`
+}
+
+func (q *Queries) ListNewLikersOldestFirst(ctx context.Context, arg ListNewLikersOldestFirstParams) ([]ListNewLikersOldestFirstRow, error) {
+	rows, err := q.db.Query(ctx, listNewLikersOldestFirst,
+		arg.RecipientUserID,
+		arg.IncludeRegular,
+		arg.IncludeSuper,
+		arg.Since,
+		arg.Until,
+		arg.CreatedAtCursor,
+		arg.IDCursor,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNewLikersOldestFirstRow
+	for rows.Next() {
+		var i ListNewLikersOldestFirstRow
+		if err := rows.Scan(
+			&i.ActorUserID,
+			&i.CreatedAt,
+			&i.ID,
+			&i.SuperLike,
+			&i.Seen,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -127,14 +773,75 @@ func (q *Queries) ListNewLikers(ctx context.Context, arg ListNewLikersParams) ([
 	return items, nil
 }
 
+const lockActorDecisions = `-- name: LockActorDecisions :exec
+SELECT pg_advisory_xact_lock(hashtextextended('decisions:' || $1::TEXT, 0))
+`
+
+func (q *Queries) LockActorDecisions(ctx context.Context, actorUserID string) error {
+	_, err := q.db.Exec(ctx, lockActorDecisions, actorUserID)
+	return err
+}
+
+const lockUserScore = `-- name: LockUserScore :one
+INSERT INTO user_scores (
+    user_id, score
+) VALUES (
+             $1, $2
+         )
+ON CONFLICT (user_id)
+    DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING user_id, score, likes_received, passes_received, updated_at
+`
+
+type LockUserScoreParams struct {
+	UserID       string  `json:"userId"`
+	InitialScore float64 `json:"initialScore"`
+}
+
+func (q *Queries) LockUserScore(ctx context.Context, arg LockUserScoreParams) (UserScore, error) {
+	row := q.db.QueryRow(ctx, lockUserScore, arg.UserID, arg.InitialScore)
+	var i UserScore
+	err := row.Scan(
+		&i.UserID,
+		&i.Score,
+		&i.LikesReceived,
+		&i.PassesReceived,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markLikesSeen = `-- name: MarkLikesSeen :one
+INSERT INTO likes_seen (
+    recipient_user_id, seen_before
+) VALUES (
+             $1, $2
+         )
+ON CONFLICT (recipient_user_id)
+    DO UPDATE SET seen_before = GREATEST(likes_seen.seen_before, EXCLUDED.seen_before), updated_at = NOW()
+RETURNING seen_before
+`
+
+type MarkLikesSeenParams struct {
+	RecipientUserID string    `json:"recipientUserId"`
+	SeenBefore      time.Time `json:"seenBefore"`
+}
+
+func (q *Queries) MarkLikesSeen(ctx context.Context, arg MarkLikesSeenParams) (time.Time, error) {
+	row := q.db.QueryRow(ctx, markLikesSeen, arg.RecipientUserID, arg.SeenBefore)
+	var seen_before time.Time
+	err := row.Scan(&seen_before)
+	return seen_before, err
+}
+
 const putDecision = `-- name: PutDecision :one
 INSERT INTO decisions (
-    actor_user_id, recipient_user_id, liked
+    actor_user_id, recipient_user_id, liked, super_like
 ) VALUES (
-             $1, $2, $3
+             $1, $2, $3, $4
          )
 ON CONFLICT (actor_user_id, recipient_user_id)
-    DO UPDATE SET liked = EXCLUDED.liked, updated_at = NOW()
+    DO UPDATE SET liked = EXCLUDED.liked, super_like = EXCLUDED.super_like, updated_at = NOW()
 RETURNING (
     EXISTS (
         SELECT 1 FROM decisions
@@ -149,11 +856,101 @@ type PutDecisionParams struct {
 	ActorUserID     string `json:"actorUserId"`
 	RecipientUserID string `json:"recipientUserId"`
 	Liked           bool   `json:"liked"`
+	SuperLike       bool   `json:"superLike"`
 }
 
 func (q *Queries) PutDecision(ctx context.Context, arg PutDecisionParams) (bool, error) {
-	row := q.db.QueryRow(ctx, putDecision, arg.ActorUserID, arg.RecipientUserID, arg.Liked)
+	row := q.db.QueryRow(ctx, putDecision,
+		arg.ActorUserID,
+		arg.RecipientUserID,
+		arg.Liked,
+		arg.SuperLike,
+	)
 	var mutual_likes bool
 	err := row.Scan(&mutual_likes)
 	return mutual_likes, err
 }
+
+const recordQuotaLike = `-- name: RecordQuotaLike :exec
+WITH pruned AS (
+    DELETE FROM quota_likes
+    WHERE quota_likes.actor_user_id = $1
+      AND quota_likes.recipient_user_id <> $2
+      AND quota_likes.created_at <= $3
+)
+INSERT INTO quota_likes (
+    actor_user_id, recipient_user_id
+) VALUES (
+             $1, $2
+         )
+ON CONFLICT (actor_user_id, recipient_user_id)
+    DO UPDATE SET created_at = NOW()
+`
+
+type RecordQuotaLikeParams struct {
+	ActorUserID     string    `json:"actorUserId"`
+	RecipientUserID string    `json:"recipientUserId"`
+	WindowStart     time.Time `json:"windowStart"`
+}
+
+func (q *Queries) RecordQuotaLike(ctx context.Context, arg RecordQuotaLikeParams) error {
+	_, err := q.db.Exec(ctx, recordQuotaLike, arg.ActorUserID, arg.RecipientUserID, arg.WindowStart)
+	return err
+}
+
+const rewindLikeExpirySweep = `-- name: RewindLikeExpirySweep :exec
+UPDATE like_expiry_sweeps
+SET swept_before = LEAST(swept_before, $1), updated_at = NOW()
+`
+
+func (q *Queries) RewindLikeExpirySweep(ctx context.Context, sweptBefore time.Time) error {
+	_, err := q.db.Exec(ctx, rewindLikeExpirySweep, sweptBefore)
+	return err
+}
+
+const saveImportCheckpoint = `-- name: SaveImportCheckpoint :exec
+INSERT INTO import_checkpoints (name, position)
+VALUES ($1, $2)
+ON CONFLICT (name)
+    DO UPDATE SET position = EXCLUDED.position, updated_at = NOW()
+`
+
+type SaveImportCheckpointParams struct {
+	Name     string `json:"name"`
+	Position int64  `json:"position"`
+}
+
+func (q *Queries) SaveImportCheckpoint(ctx context.Context, arg SaveImportCheckpointParams) error {
+	_, err := q.db.Exec(ctx, saveImportCheckpoint, arg.Name, arg.Position)
+	return err
+}
+
+const setUserScore = `-- name: SetUserScore :exec
+INSERT INTO user_scores (
+    user_id, score, likes_received, passes_received
+) VALUES (
+             $1, $2, $3, $4
+         )
+ON CONFLICT (user_id)
+    DO UPDATE SET score = EXCLUDED.score,
+                  likes_received = EXCLUDED.likes_received,
+                  passes_received = EXCLUDED.passes_received,
+                  updated_at = NOW()
+`
+
+type SetUserScoreParams struct {
+	UserID         string  `json:"userId"`
+	Score          float64 `json:"score"`
+	LikesReceived  int64   `json:"likesReceived"`
+	PassesReceived int64   `json:"passesReceived"`
+}
+
+func (q *Queries) SetUserScore(ctx context.Context, arg SetUserScoreParams) error {
+	_, err := q.db.Exec(ctx, setUserScore,
+		arg.UserID,
+		arg.Score,
+		arg.LikesReceived,
+		arg.PassesReceived,
+	)
+	return err
+}
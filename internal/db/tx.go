@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Store runs queries on their own, or together in a transaction
+type Store interface {
+	Querier
+	// InTx runs fn with queries bound to a single transaction, committing if
+	// fn returns nil and rolling back otherwise
+	InTx(ctx context.Context, fn func(Querier) error) error
+}
+
+var _ Store = (*Queries)(nil)
+
+// InTx runs fn in a new transaction, or a savepoint if the queries are already
+// bound to one
+func (q *Queries) InTx(ctx context.Context, fn func(Querier) error) error {
+	beginner, ok := q.db.(interface {
+		Begin(ctx context.Context) (pgx.Tx, error)
+	})
+	if !ok {
+		return fmt.Errorf("failed to begin transaction: %T doesn't support transactions", q.db)
+	}
+
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
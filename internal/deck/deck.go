@@ -0,0 +1,86 @@
+package deck
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned when a pagination token can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the pool position of the last candidate served. Pages follow
+// the pool order, which doesn't change as candidates like or stop liking the
+// actor, so a cursor stays valid and never skips or repeats a candidate.
+type Cursor struct {
+	Position int `json:"p"`
+}
+
+// Encode returns the cursor as an opaque pagination token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a pagination token, returning nil for an empty token
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Position < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Dedupe returns the pool without duplicates or the actor, keeping the first
+// occurrence of each candidate
+func Dedupe(pool []string, actorUserID string) []string {
+	seen := make(map[string]bool, len(pool))
+	unique := make([]string, 0, len(pool))
+	for _, userID := range pool {
+		if userID == "" || userID == actorUserID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		unique = append(unique, userID)
+	}
+	return unique
+}
+
+// Page returns up to limit candidates from a deduplicated pool that come after
+// the cursor, skipping users the actor has decided on. Users who liked the
+// actor are put first within the page. The returned cursor is nil when there
+// are no more candidates.
+func Page(pool []string, decided, likedActor map[string]bool, after *Cursor, limit int) ([]string, *Cursor) {
+	start := 0
+	if after != nil {
+		start = after.Position + 1
+	}
+
+	var liked, rest []string
+	last := -1
+	for i := start; i < len(pool); i++ {
+		userID := pool[i]
+		if decided[userID] {
+			continue
+		}
+		if len(liked)+len(rest) == limit {
+			return append(liked, rest...), &Cursor{Position: last}
+		}
+		if likedActor[userID] {
+			liked = append(liked, userID)
+		} else {
+			rest = append(rest, userID)
+		}
+		last = i
+	}
+	return append(liked, rest...), nil
+}
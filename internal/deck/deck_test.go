@@ -0,0 +1,108 @@
+package deck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupe(t *testing.T) {
+	pool := Dedupe([]string{"user2", "user1", "user3", "user2", "", "user4", "user3"}, "user1")
+	assert.Equal(t, []string{"user2", "user3", "user4"}, pool)
+}
+
+func TestPage(t *testing.T) {
+	pool := []string{"user2", "user3", "user4", "user5", "user6"}
+	decided := map[string]bool{"user3": true}
+	likedActor := map[string]bool{"user4": true}
+
+	page, next := Page(pool, decided, likedActor, nil, 2)
+	assert.Equal(t, []string{"user4", "user2"}, page)
+	require.NotNil(t, next)
+
+	// Deciding on candidates between pages doesn't shift the cursor
+	decided["user4"] = true
+	decided["user2"] = true
+	page, next = Page(pool, decided, likedActor, next, 2)
+	assert.Equal(t, []string{"user5", "user6"}, page)
+	assert.Nil(t, next)
+}
+
+// pageAll pages through the pool, calling between after each page but the last
+func pageAll(t *testing.T, pool []string, likedActor map[string]bool, limit int, between func()) [][]string {
+	var pages [][]string
+	var cursor *Cursor
+	for {
+		page, next := Page(pool, nil, likedActor, cursor, limit)
+		pages = append(pages, page)
+		if next == nil {
+			return pages
+		}
+		between()
+		// Round trip through the pagination token like a client would
+		decoded, err := DecodeCursor(next.Encode())
+		require.NoError(t, err)
+		cursor = decoded
+	}
+}
+
+func TestPage_NoDuplicatesAcrossPages(t *testing.T) {
+	pool := []string{"user2", "user3", "user4", "user5", "user6", "user7", "user8"}
+	likedActor := map[string]bool{"user4": true, "user7": true}
+
+	pages := pageAll(t, pool, likedActor, 3, func() {})
+	assert.Equal(t, [][]string{{"user4", "user2", "user3"}, {"user7", "user5", "user6"}, {"user8"}}, pages)
+}
+
+func TestPage_LateLiker(t *testing.T) {
+	pool := []string{"user2", "user3", "user4", "user5"}
+	likedActor := map[string]bool{}
+
+	// A candidate who likes the actor after the first page is still served
+	pages := pageAll(t, pool, likedActor, 2, func() { likedActor["user5"] = true })
+	assert.Equal(t, [][]string{{"user2", "user3"}, {"user5", "user4"}}, pages)
+}
+
+func TestPage_FlippedLiker(t *testing.T) {
+	pool := []string{"user2", "user3", "user4", "user5"}
+	likedActor := map[string]bool{"user3": true}
+
+	// A liker who changes to a pass after being served isn't served again
+	pages := pageAll(t, pool, likedActor, 2, func() { delete(likedActor, "user3") })
+	assert.Equal(t, [][]string{{"user3", "user2"}, {"user4", "user5"}}, pages)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	cursor, err := DecodeCursor("")
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	for _, token := range []string{"not base64!", "bm90IGpzb24", Cursor{Position: -1}.Encode()} {
+		_, err := DecodeCursor(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candidates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"*": ["user1", "user2"], "user3": ["user4"]}`), 0o600))
+
+	source, err := LoadFile(path)
+	require.NoError(t, err)
+
+	pool, err := source.Candidates(context.Background(), "user3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user4"}, pool)
+
+	pool, err = source.Candidates(context.Background(), "user9")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, pool)
+
+	require.NoError(t, os.WriteFile(path, []byte(`["user1"]`), 0o600))
+	_, err = LoadFile(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultPool is the key of the candidate pool served to actors without a pool of their own
+const DefaultPool = "*"
+
+// CandidateSource supplies the pool of users an actor may be shown, in the
+// order they should be shown. Pools may contain users the actor has already
+// decided on, the actor themselves, or duplicates: these are filtered out when
+// the deck is built.
+type CandidateSource interface {
+	Candidates(ctx context.Context, actorUserID string) ([]string, error)
+}
+
+// StaticSource serves fixed candidate pools keyed by actor, falling back to the DefaultPool
+type StaticSource struct {
+	pools map[string][]string
+}
+
+var _ CandidateSource = (*StaticSource)(nil)
+
+// NewStaticSource creates a source serving the given pools
+func NewStaticSource(pools map[string][]string) *StaticSource {
+	return &StaticSource{pools: pools}
+}
+
+// LoadFile reads candidate pools from a JSON file mapping actor user IDs to
+// their candidates, with "*" holding the pool for every other actor:
+//
+//	{"*": ["user1", "user2"], "user3": ["user4"]}
+func LoadFile(path string) (*StaticSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read candidates file: %w", err)
+	}
+
+	var pools map[string][]string
+	if err := json.Unmarshal(data, &pools); err != nil {
+		return nil, fmt.Errorf("failed to parse candidates file: %w", err)
+	}
+	return NewStaticSource(pools), nil
+}
+
+// Candidates implements CandidateSource
+func (s *StaticSource) Candidates(_ context.Context, actorUserID string) ([]string, error) {
+	if pool, ok := s.pools[actorUserID]; ok {
+		return pool, nil
+	}
+	return s.pools[DefaultPool], nil
+}
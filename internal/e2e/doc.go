@@ -0,0 +1,5 @@
+// Package e2e holds the end-to-end test suite, which calls the service wired as
+// in production through the gRPC server and its interceptors over an in-memory
+// connection, backed by a real Postgres. See postgres_test.go for how the
+// database is provided.
+package e2e
@@ -0,0 +1,406 @@
+package e2e
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"muzz-explore-service/internal/app"
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/config"
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/events"
+	"muzz-explore-service/internal/expiry"
+	"muzz-explore-service/internal/importer"
+	"muzz-explore-service/internal/logging"
+	"muzz-explore-service/internal/seed"
+	"muzz-explore-service/internal/tracing"
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+// pageSize is how many likers the service returns per page
+const pageSize = 50
+
+// testKey signs the bearer tokens the suite authenticates with
+const testKey = "e2e-secret"
+
+// testArgs configure the service under test, as command-line flags override
+// the defaults and environment in production. Likers are visible to every
+// recipient, so lists can be compared with the decisions made.
+var testArgs = []string{
+	"-auth-mode=jwt",
+	"-auth-static-key=" + testKey,
+	"-likes-visibility-gate=false",
+	"-trace-exporter=none",
+}
+
+// serve empties the test database and serves the service against it, built by
+// the server command's wiring and served by the production gRPC server over an
+// in-memory listener. args override the test configuration.
+func serve(t *testing.T, args ...string) (*bufconn.Listener, *pgxpool.Pool) {
+	t.Helper()
+	requireDatabase(t)
+
+	cfg, err := config.Load(flag.NewFlagSet("e2e", flag.ContinueOnError), append(slices.Clone(testArgs), args...))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	_, err = pool.Exec(ctx, `TRUNCATE decisions, quota_likes, user_scores, likes_seen, expired_likes, like_expiry_sweeps, import_checkpoints RESTART IDENTITY`)
+	require.NoError(t, err)
+
+	logger, err := logging.New(io.Discard, cfg)
+	require.NoError(t, err)
+	redactor, err := logging.NewUserIDRedactor(cfg.LogUserIDs, cfg.LogUserIDKey)
+	require.NoError(t, err)
+	tp, shutdownTracing, err := tracing.Setup(ctx, cfg)
+	require.NoError(t, err)
+
+	explore, err := app.New(cfg, pool, logger, redactor, tp)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	go explore.Server.Serve(listener)
+	t.Cleanup(func() {
+		explore.Server.GracefulStop()
+		shutdownTracing(ctx)
+	})
+
+	return listener, pool
+}
+
+// dial connects to the served service, authenticating every call with token
+// unless it's empty
+func dial(t *testing.T, listener *bufconn.Listener, token string) pb.ExploreServiceClient {
+	t.Helper()
+
+	opts := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(token)))
+	}
+	conn, err := grpc.NewClient("passthrough:///e2e", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewExploreServiceClient(conn)
+}
+
+// bearerToken sends a token in the authorization metadata. The in-memory
+// connection has no transport security to require.
+type bearerToken string
+
+func (b bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+
+func (b bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// token signs a token for subject with the given roles
+func token(t *testing.T, subject string, roles ...string) string {
+	t.Helper()
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: roles,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testKey))
+	require.NoError(t, err)
+	return signed
+}
+
+// newClient serves the service and returns a client calling it as a backend
+// with the internal role, which may act on behalf of any user
+func newClient(t *testing.T, args ...string) (pb.ExploreServiceClient, *pgxpool.Pool) {
+	t.Helper()
+	listener, pool := serve(t, args...)
+	return dial(t, listener, token(t, "e2e-backend", auth.RoleInternal)), pool
+}
+
+// decide records a decision, returning whether it made a mutual like
+func decide(t *testing.T, client pb.ExploreServiceClient, actor, recipient string, liked bool) bool {
+	t.Helper()
+	resp, err := client.PutDecision(context.Background(), &pb.PutDecisionRequest{
+		ActorUserId:     actor,
+		RecipientUserId: recipient,
+		LikedRecipient:  liked,
+	})
+	require.NoError(t, err)
+	return resp.MutualLikes
+}
+
+type listFunc func(context.Context, *pb.ListLikedYouRequest, ...grpc.CallOption) (*pb.ListLikedYouResponse, error)
+
+// listAll follows pagination tokens to the last page, returning the likers in
+// the order they were listed and the number of pages
+func listAll(t *testing.T, list listFunc, recipient string, order pb.SortOrder) ([]string, int) {
+	t.Helper()
+
+	var likers []string
+	seen := map[string]bool{}
+	req := &pb.ListLikedYouRequest{RecipientUserId: recipient, SortOrder: order}
+	for pages := 1; ; pages++ {
+		resp, err := list(context.Background(), req)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(resp.Likers), pageSize)
+
+		for _, liker := range resp.Likers {
+			require.False(t, seen[liker.ActorId], "%s listed twice", liker.ActorId)
+			seen[liker.ActorId] = true
+			likers = append(likers, liker.ActorId)
+		}
+
+		if resp.GetNextPaginationToken() == "" {
+			return likers, pages
+		}
+		require.Len(t, resp.Likers, pageSize, "only the last page is partial")
+		req.PaginationToken = resp.NextPaginationToken
+	}
+}
+
+func count(t *testing.T, client pb.ExploreServiceClient, recipient string) int {
+	t.Helper()
+	resp, err := client.CountLikedYou(context.Background(), &pb.CountLikedYouRequest{RecipientUserId: recipient})
+	require.NoError(t, err)
+	return int(resp.Count)
+}
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		likes int
+		pages int
+	}{
+		{likes: 0, pages: 1},
+		{likes: 1, pages: 1},
+		{likes: pageSize, pages: 1},
+		{likes: pageSize + 1, pages: 2},
+		{likes: pageSize + 2, pages: 2},
+		{likes: 2 * pageSize, pages: 2},
+		{likes: 2*pageSize + 1, pages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d likes", tt.likes), func(t *testing.T) {
+			client, _ := newClient(t)
+
+			var likers []string
+			for i := 1; i <= tt.likes; i++ {
+				liker := fmt.Sprintf("tester%d", i)
+				decide(t, client, liker, "user2", true)
+				likers = append(likers, liker)
+			}
+			// A pass and a like for someone else are never listed
+			decide(t, client, "passer", "user2", false)
+			decide(t, client, "tester1", "user3", true)
+
+			oldestFirst, pages := listAll(t, client.ListLikedYou, "user2", pb.SortOrder_SORT_ORDER_OLDEST_FIRST)
+			assert.Equal(t, tt.pages, pages)
+			assert.Equal(t, likers, oldestFirst)
+
+			newestFirst, pages := listAll(t, client.ListLikedYou, "user2", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+			assert.Equal(t, tt.pages, pages)
+			slices.Reverse(likers)
+			assert.Equal(t, likers, newestFirst)
+
+			newLikers, _ := listAll(t, client.ListNewLikedYou, "user2", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+			assert.Equal(t, likers, newLikers, "user2 hasn't liked anyone back")
+			assert.Equal(t, tt.likes, count(t, client, "user2"))
+		})
+	}
+}
+
+// TestPagination_SeedData pages through seeded likes, many made at exactly the
+// same time, so page boundaries fall between likes that only differ by ID
+func TestPagination_SeedData(t *testing.T) {
+	client, pool := newClient(t)
+
+	cfg := seed.DefaultConfig()
+	cfg.Users = 300
+	cfg.PopularLikers = 200
+	cfg.CollisionRatio = 0.5
+	dataset, err := seed.Generate(cfg)
+	require.NoError(t, err)
+	_, err = dataset.Load(context.Background(), importer.NewPostgresStore(pool), "e2e", 0)
+	require.NoError(t, err)
+
+	popular := seed.User(0)
+	likers := dataset.Likers(popular, false)
+	newLikers := dataset.Likers(popular, true)
+	require.Greater(t, len(likers), 3*pageSize)
+	require.Less(t, len(newLikers), len(likers))
+
+	newestFirst, pages := listAll(t, client.ListLikedYou, popular, pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, (len(likers)+pageSize-1)/pageSize, pages)
+	assert.ElementsMatch(t, likers, newestFirst)
+
+	oldestFirst, _ := listAll(t, client.ListLikedYou, popular, pb.SortOrder_SORT_ORDER_OLDEST_FIRST)
+	slices.Reverse(oldestFirst)
+	assert.Equal(t, newestFirst, oldestFirst, "both orders break ties the same way")
+
+	listedNew, _ := listAll(t, client.ListNewLikedYou, popular, pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.ElementsMatch(t, newLikers, listedNew)
+	assert.Equal(t, len(likers), count(t, client, popular))
+
+	// Every user's likes match the generated graph, not just the popular user's
+	for i := 1; i < cfg.Users; i += 37 {
+		user := seed.User(i)
+		listed, _ := listAll(t, client.ListLikedYou, user, pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+		assert.ElementsMatch(t, dataset.Likers(user, false), listed, user)
+		listed, _ = listAll(t, client.ListNewLikedYou, user, pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+		assert.ElementsMatch(t, dataset.Likers(user, true), listed, user)
+	}
+}
+
+func TestMutualLikes(t *testing.T) {
+	client, _ := newClient(t)
+
+	assert.False(t, decide(t, client, "alice", "bob", true), "bob hasn't decided yet")
+	assert.False(t, decide(t, client, "bob", "alice", false), "bob passed")
+	assert.False(t, decide(t, client, "carol", "alice", true))
+
+	newLikers, _ := listAll(t, client.ListNewLikedYou, "alice", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"carol"}, newLikers)
+
+	assert.True(t, decide(t, client, "bob", "alice", true), "bob likes back after passing")
+
+	likers, _ := listAll(t, client.ListLikedYou, "alice", pb.SortOrder_SORT_ORDER_OLDEST_FIRST)
+	assert.Equal(t, []string{"bob", "carol"}, likers, "bob's like keeps the time of the first decision, before carol's like")
+	newLikers, _ = listAll(t, client.ListNewLikedYou, "alice", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"carol"}, newLikers, "mutual likes aren't new")
+	newLikers, _ = listAll(t, client.ListNewLikedYou, "bob", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Empty(t, newLikers)
+
+	likers, _ = listAll(t, client.ListLikedYou, "bob", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"alice"}, likers)
+
+	// Liking again reports the mutual like again
+	assert.True(t, decide(t, client, "alice", "bob", true))
+}
+
+func TestFlips(t *testing.T) {
+	client, _ := newClient(t)
+
+	decide(t, client, "alice", "bob", true)
+	decide(t, client, "carol", "bob", true)
+	assert.Equal(t, 2, count(t, client, "bob"))
+
+	// Like to pass drops the like everywhere
+	decide(t, client, "alice", "bob", false)
+	likers, _ := listAll(t, client.ListLikedYou, "bob", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"carol"}, likers)
+	assert.Equal(t, 1, count(t, client, "bob"))
+
+	// Pass back to like restores it in its original position
+	decide(t, client, "alice", "bob", true)
+	likers, _ = listAll(t, client.ListLikedYou, "bob", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"carol", "alice"}, likers)
+	assert.Equal(t, 2, count(t, client, "bob"))
+
+	// Flipping one side of a mutual like makes the other side's like new again
+	assert.True(t, decide(t, client, "bob", "alice", true))
+	newLikers, _ := listAll(t, client.ListNewLikedYou, "bob", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"carol"}, newLikers)
+
+	decide(t, client, "bob", "alice", false)
+	newLikers, _ = listAll(t, client.ListNewLikedYou, "bob", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Equal(t, []string{"carol", "alice"}, newLikers)
+
+	likers, _ = listAll(t, client.ListLikedYou, "alice", pb.SortOrder_SORT_ORDER_NEWEST_FIRST)
+	assert.Empty(t, likers, "bob passed on alice")
+}
+
+func TestLikeExpirySweep(t *testing.T) {
+	client, pool := newClient(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sweeper := expiry.NewSweeper(db.New(pool), events.NewLogPublisher(logger, logging.UserIDRedactor{}), 24*time.Hour, time.Hour, logger)
+	sweep := func() int {
+		expired, err := sweeper.Sweep(ctx)
+		require.NoError(t, err)
+		return expired
+	}
+
+	// A pass made before the cutoff, so the sweep's watermark has moved past it
+	decide(t, client, "alice", "bob", false)
+	_, err := pool.Exec(ctx, `UPDATE decisions SET created_at = NOW() - INTERVAL '48 hours', updated_at = NOW() - INTERVAL '48 hours'`)
+	require.NoError(t, err)
+	assert.Zero(t, sweep())
+
+	// Flipping the pass to a like keeps its creation time, so it's already expired
+	decide(t, client, "alice", "bob", true)
+	assert.Equal(t, 1, sweep())
+
+	// Imported history is older than the watermark too
+	old := time.Now().Add(-72 * time.Hour)
+	_, err = importer.NewPostgresStore(pool).Merge(ctx, "e2e", []importer.Record{
+		{ActorUserID: "carol", RecipientUserID: "bob", Liked: true, CreatedAt: old, UpdatedAt: old},
+	}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sweep())
+	assert.Zero(t, sweep(), "likes are archived once")
+}
+
+func TestImportMerge(t *testing.T) {
+	_, pool := newClient(t)
+	ctx := context.Background()
+	store := importer.NewPostgresStore(pool)
+	at := func(hours int) time.Time { return time.Date(2024, 1, 1, hours, 0, 0, 0, time.UTC) }
+	merge := func(position int64, records ...importer.Record) importer.MergeResult {
+		result, err := store.Merge(ctx, "e2e", records, position)
+		require.NoError(t, err)
+		return result
+	}
+	stored := func() (liked bool, createdAt, updatedAt time.Time) {
+		err := pool.QueryRow(ctx, `SELECT liked, created_at, updated_at FROM decisions WHERE actor_user_id = 'alice' AND recipient_user_id = 'bob'`).
+			Scan(&liked, &createdAt, &updatedAt)
+		require.NoError(t, err)
+		return liked, createdAt.UTC(), updatedAt.UTC()
+	}
+
+	result := merge(1, importer.Record{ActorUserID: "alice", RecipientUserID: "bob", Liked: true, CreatedAt: at(10), UpdatedAt: at(12)})
+	assert.Equal(t, int64(1), result.Inserted)
+
+	// A stale record still moves created_at earlier, keeping the stored decision
+	result = merge(2, importer.Record{ActorUserID: "alice", RecipientUserID: "bob", Liked: false, CreatedAt: at(8), UpdatedAt: at(9)})
+	assert.Equal(t, int64(1), result.Updated)
+	liked, createdAt, updatedAt := stored()
+	assert.True(t, liked)
+	assert.Equal(t, at(8), createdAt)
+	assert.Equal(t, at(12), updatedAt)
+
+	// A later record replaces the decision without moving created_at later
+	merge(3, importer.Record{ActorUserID: "alice", RecipientUserID: "bob", Liked: false, CreatedAt: at(11), UpdatedAt: at(13)})
+	liked, createdAt, updatedAt = stored()
+	assert.False(t, liked)
+	assert.Equal(t, at(8), createdAt)
+	assert.Equal(t, at(13), updatedAt)
+
+	// Records that change nothing are stale
+	result = merge(4, importer.Record{ActorUserID: "alice", RecipientUserID: "bob", Liked: true, CreatedAt: at(9), UpdatedAt: at(11)})
+	assert.Equal(t, int64(1), result.Stale)
+}
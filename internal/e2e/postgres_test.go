@@ -0,0 +1,202 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"muzz-explore-service/internal/db"
+)
+
+// databaseURL is the migrated database the suite runs against, empty if
+// Postgres isn't available and the tests should skip
+var databaseURL string
+
+// skipReason explains why databaseURL is empty
+var skipReason = "Postgres is not available"
+
+// TestMain provides a fresh database for the suite: a new database on the
+// server at TEST_DATABASE_URL if set, otherwise an ephemeral local instance
+// started with initdb and pg_ctl if they are installed. Both are removed
+// once the tests finish. Without either the tests skip, unless
+// E2E_REQUIRE_POSTGRES is set, and failing to set up a database configured
+// by TEST_DATABASE_URL always fails the suite.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	code, err := run(m)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+func run(m *testing.M) (int, error) {
+	if testing.Short() {
+		skipReason = "end-to-end tests are skipped in short mode"
+		return m.Run(), nil
+	}
+
+	var (
+		dbURL   string
+		cleanup func()
+		err     error
+	)
+	if adminURL := os.Getenv("TEST_DATABASE_URL"); adminURL != "" {
+		dbURL, cleanup, err = createDatabase(adminURL)
+	} else {
+		dbURL, cleanup, err = startPostgres()
+		if errors.Is(err, errNoPostgres) && os.Getenv("E2E_REQUIRE_POSTGRES") == "" {
+			skipReason = err.Error()
+			return m.Run(), nil
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer cleanup()
+
+	migrator, err := db.NewMigrator(dbURL)
+	if err != nil {
+		return 0, err
+	}
+	defer migrator.Close()
+	if err := migrator.Up(); err != nil {
+		return 0, fmt.Errorf("failed to migrate test database: %w", err)
+	}
+
+	databaseURL = dbURL
+	return m.Run(), nil
+}
+
+// requireDatabase skips the test unless the suite has a database
+func requireDatabase(t *testing.T) {
+	t.Helper()
+	if databaseURL == "" {
+		t.Skip(skipReason)
+	}
+}
+
+// createDatabase creates a uniquely named database on the server at adminURL,
+// returning its URL and a function dropping it
+func createDatabase(adminURL string) (string, func(), error) {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, adminURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to TEST_DATABASE_URL: %w", err)
+	}
+
+	name := fmt.Sprintf("explore_e2e_%d_%d", os.Getpid(), time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		conn.Close(ctx)
+		return "", nil, fmt.Errorf("failed to create test database: %w", err)
+	}
+
+	parsed, err := url.Parse(adminURL)
+	if err != nil {
+		conn.Close(ctx)
+		return "", nil, fmt.Errorf("TEST_DATABASE_URL must be a URL: %w", err)
+	}
+	parsed.Path = "/" + name
+
+	drop := func() {
+		if _, err := conn.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to drop test database %s: %v\n", name, err)
+		}
+		conn.Close(ctx)
+	}
+	return parsed.String(), drop, nil
+}
+
+var errNoPostgres = errors.New("Postgres is not available: set TEST_DATABASE_URL or install initdb and pg_ctl")
+
+// startPostgres initializes and starts a throwaway Postgres instance in a
+// temporary directory, returning its URL and a function removing it
+func startPostgres() (string, func(), error) {
+	initdb, pgctl, ok := findPostgres()
+	if !ok {
+		return "", nil, errNoPostgres
+	}
+	if os.Geteuid() == 0 {
+		return "", nil, fmt.Errorf("%w: Postgres refuses to run as root", errNoPostgres)
+	}
+
+	dir, err := os.MkdirTemp("", "explore-e2e-")
+	if err != nil {
+		return "", nil, err
+	}
+	data := filepath.Join(dir, "data")
+	removeDir := func() { os.RemoveAll(dir) }
+
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "-E", "UTF8", "--no-sync").CombinedOutput(); err != nil {
+		removeDir()
+		return "", nil, fmt.Errorf("initdb failed: %w\n%s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		removeDir()
+		return "", nil, err
+	}
+	options := fmt.Sprintf("-p %d -k %s -c listen_addresses=localhost -c fsync=off -c synchronous_commit=off", port, dir)
+	if out, err := exec.Command(pgctl, "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		removeDir()
+		return "", nil, fmt.Errorf("pg_ctl start failed: %w\n%s", err, out)
+	}
+
+	stop := func() {
+		if out, err := exec.Command(pgctl, "-D", data, "-m", "immediate", "stop").CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "pg_ctl stop failed: %v\n%s", err, out)
+		}
+		removeDir()
+	}
+	return fmt.Sprintf("postgres://postgres@localhost:%d/postgres?sslmode=disable", port), stop, nil
+}
+
+// findPostgres looks for the server binaries on the PATH, then where Debian
+// and Ubuntu packages install them
+func findPostgres() (initdb, pgctl string, ok bool) {
+	initdb, err1 := exec.LookPath("initdb")
+	pgctl, err2 := exec.LookPath("pg_ctl")
+	if err1 == nil && err2 == nil {
+		return initdb, pgctl, true
+	}
+
+	dirs, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	for _, dir := range dirs {
+		initdb, pgctl = filepath.Join(dir, "initdb"), filepath.Join(dir, "pg_ctl")
+		if _, err := os.Stat(initdb); err != nil {
+			continue
+		}
+		if _, err := os.Stat(pgctl); err == nil {
+			return initdb, pgctl, true
+		}
+	}
+	return "", "", false
+}
+
+// freePort returns a TCP port on localhost nothing is listening on
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
@@ -0,0 +1,119 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"muzz-explore-service/internal/auth"
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+func TestAuthentication(t *testing.T) {
+	listener, _ := serve(t)
+
+	tests := []struct {
+		name  string
+		token string
+		code  codes.Code
+	}{
+		{name: "no token", token: "", code: codes.Unauthenticated},
+		{name: "invalid token", token: "not-a-token", code: codes.Unauthenticated},
+		{name: "another user", token: token(t, "carol"), code: codes.PermissionDenied},
+		{name: "the actor", token: token(t, "alice"), code: codes.OK},
+		{name: "internal service", token: token(t, "backend", auth.RoleInternal), code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dial(t, listener, tt.token).PutDecision(context.Background(), &pb.PutDecisionRequest{
+				ActorUserId:     "alice",
+				RecipientUserId: "bob",
+				LikedRecipient:  true,
+			})
+			assert.Equal(t, tt.code, status.Code(err), err)
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	client, _ := newClient(t, "-rate-limit-enabled", "-rate-limits=PutDecision=0.001:2")
+	put := func(actor, recipient string, opts ...grpc.CallOption) error {
+		_, err := client.PutDecision(context.Background(), &pb.PutDecisionRequest{
+			ActorUserId:     actor,
+			RecipientUserId: recipient,
+		}, opts...)
+		return err
+	}
+
+	require.NoError(t, put("alice", "bob"))
+	require.NoError(t, put("alice", "carol"))
+
+	var header metadata.MD
+	err := put("alice", "dave", grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.NotEmpty(t, header.Get("retry-after"))
+
+	// Each user behind the same backend has their own limit
+	assert.NoError(t, put("bob", "alice"))
+}
+
+func TestQuota(t *testing.T) {
+	client, _ := newClient(t, "-like-quota-limit=3", "-premium-user-ids=premium")
+	like := func(actor, recipient string, liked bool) error {
+		_, err := client.PutDecision(context.Background(), &pb.PutDecisionRequest{
+			ActorUserId:     actor,
+			RecipientUserId: recipient,
+			LikedRecipient:  liked,
+		})
+		return err
+	}
+
+	// Concurrent likes can't claim more than the quota between them
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		liked    []string
+		rejected int
+	)
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recipient := fmt.Sprintf("user%d", i)
+			err := like("alice", recipient, true)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch status.Code(err) {
+			case codes.OK:
+				liked = append(liked, recipient)
+			case codes.ResourceExhausted:
+				rejected++
+			default:
+				t.Errorf("unexpected error liking %s: %v", recipient, err)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, liked, 3)
+	assert.Equal(t, 7, rejected)
+
+	// Liking someone already liked and passing stay free
+	assert.NoError(t, like("alice", liked[0], true))
+	assert.NoError(t, like("alice", "user10", false))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(like("alice", "user11", true)))
+
+	// Premium users have no quota
+	for i := range 5 {
+		assert.NoError(t, like("premium", fmt.Sprintf("user%d", i), true))
+	}
+}
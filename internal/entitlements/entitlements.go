@@ -0,0 +1,45 @@
+package entitlements
+
+import (
+	"context"
+	"strings"
+)
+
+// Tier is a user's subscription level
+type Tier string
+
+const (
+	TierFree    Tier = "free"
+	TierPremium Tier = "premium"
+)
+
+// Entitlements looks up which tier a user is subscribed to
+type Entitlements interface {
+	Tier(ctx context.Context, userID string) (Tier, error)
+}
+
+// Static grants the premium tier to a fixed set of users, everyone else is free
+type Static struct {
+	premium map[string]bool
+}
+
+var _ Entitlements = (*Static)(nil)
+
+// NewStatic creates entitlements granting premium to the given user IDs
+func NewStatic(premiumUserIDs []string) *Static {
+	premium := make(map[string]bool, len(premiumUserIDs))
+	for _, id := range premiumUserIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			premium[id] = true
+		}
+	}
+	return &Static{premium: premium}
+}
+
+// Tier implements Entitlements
+func (s *Static) Tier(_ context.Context, userID string) (Tier, error) {
+	if s.premium[userID] {
+		return TierPremium, nil
+	}
+	return TierFree, nil
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"muzz-explore-service/internal/logging"
+)
+
+// TypeLikeExpired is emitted when an unanswered like passes the expiry age
+const TypeLikeExpired = "like_expired"
+
+// Event is a notable change to a decision, for consumption by other services
+type Event struct {
+	Type            string
+	ActorUserID     string
+	RecipientUserID string
+	OccurredAt      time.Time
+	Attributes      map[string]string
+}
+
+// Publisher delivers events to downstream consumers
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to a structured log, for log-based pipelines or
+// until a message broker is wired in
+type LogPublisher struct {
+	logger   *slog.Logger
+	redactor logging.UserIDRedactor
+}
+
+var _ Publisher = (*LogPublisher)(nil)
+
+// NewLogPublisher creates a publisher logging events at info level, with
+// user IDs written as the redactor allows
+func NewLogPublisher(logger *slog.Logger, redactor logging.UserIDRedactor) *LogPublisher {
+	return &LogPublisher{logger: logger, redactor: redactor}
+}
+
+// Publish implements Publisher
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	attrs := []any{
+		"event_type", event.Type,
+		p.redactor.Attr("actor_user_id", event.ActorUserID),
+		p.redactor.Attr("recipient_user_id", event.RecipientUserID),
+		"occurred_at", event.OccurredAt,
+	}
+	for k, v := range event.Attributes {
+		attrs = append(attrs, k, v)
+	}
+	p.logger.InfoContext(ctx, "event", attrs...)
+	return nil
+}
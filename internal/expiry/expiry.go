@@ -0,0 +1,123 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/events"
+)
+
+const sweepBatchSize = 500
+
+// ExpiredBefore returns the creation time before which likes are expired,
+// or zero time if likes never expire
+func ExpiredBefore(now time.Time, maxAge time.Duration) time.Time {
+	if maxAge <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-maxAge)
+}
+
+// Sweeper periodically archives unanswered likes older than the expiry age
+// to the expired_likes table and publishes a like_expired event for each.
+// The decisions themselves are left in place. Each sweep only reads likes
+// made or changed since the previous sweep's cutoff, so likes answered by the
+// time they expire are never archived. Imports rewind the cutoff to their
+// earliest like.
+type Sweeper struct {
+	queries   db.Querier
+	publisher events.Publisher
+	maxAge    time.Duration
+	interval  time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewSweeper creates a sweeper expiring likes older than maxAge every interval
+func NewSweeper(queries db.Querier, publisher events.Publisher, maxAge, interval time.Duration, logger *slog.Logger) *Sweeper {
+	return &Sweeper{
+		queries:   queries,
+		publisher: publisher,
+		maxAge:    maxAge,
+		interval:  interval,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run sweeps every interval until the context is cancelled
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := s.Sweep(ctx)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "like expiry sweep failed", "error", err)
+		} else if expired > 0 {
+			s.logger.InfoContext(ctx, "expired likes", "count", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep archives every like that has expired since the last sweep and returns
+// how many were archived. Events are published after their likes are archived,
+// so an event is lost, never duplicated, if publishing fails.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	expiredBefore := ExpiredBefore(s.now(), s.maxAge)
+	if expiredBefore.IsZero() {
+		return 0, nil
+	}
+
+	total := 0
+	for {
+		expired, err := s.queries.ArchiveExpiredLikes(ctx, db.ArchiveExpiredLikesParams{
+			ExpiredBefore: expiredBefore,
+			BatchSize:     sweepBatchSize,
+		})
+		if err != nil {
+			return total, fmt.Errorf("failed to archive expired likes: %w", err)
+		}
+		total += len(expired)
+
+		// Later sweeps start from the newest like checked, which is the cutoff once every expired like is archived
+		sweptBefore := expiredBefore
+		if len(expired) == sweepBatchSize {
+			sweptBefore = time.Time{}
+			for _, like := range expired {
+				if like.LikedAt.After(sweptBefore) {
+					sweptBefore = like.LikedAt
+				}
+			}
+		}
+		if err := s.queries.AdvanceLikeExpirySweep(ctx, sweptBefore); err != nil {
+			return total, fmt.Errorf("failed to save like expiry sweep: %w", err)
+		}
+
+		for _, like := range expired {
+			err := s.publisher.Publish(ctx, events.Event{
+				Type:            events.TypeLikeExpired,
+				ActorUserID:     like.ActorUserID,
+				RecipientUserID: like.RecipientUserID,
+				OccurredAt:      like.ExpiredAt,
+				Attributes:      map[string]string{"liked_at": like.LikedAt.UTC().Format(time.RFC3339)},
+			})
+			if err != nil {
+				s.logger.ErrorContext(ctx, "failed to publish like expired event", "error", err)
+			}
+		}
+
+		if len(expired) < sweepBatchSize {
+			return total, nil
+		}
+	}
+}
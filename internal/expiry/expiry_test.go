@@ -0,0 +1,91 @@
+package expiry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/events"
+)
+
+// fakeQueries archives likes from a fixed list in batches
+type fakeQueries struct {
+	db.Querier
+	pending []db.ExpiredLike
+	calls   []db.ArchiveExpiredLikesParams
+	swept   []time.Time
+}
+
+func (f *fakeQueries) AdvanceLikeExpirySweep(_ context.Context, sweptBefore time.Time) error {
+	f.swept = append(f.swept, sweptBefore)
+	return nil
+}
+
+func (f *fakeQueries) ArchiveExpiredLikes(_ context.Context, arg db.ArchiveExpiredLikesParams) ([]db.ExpiredLike, error) {
+	f.calls = append(f.calls, arg)
+	n := min(int(arg.BatchSize), len(f.pending))
+	batch := f.pending[:n]
+	f.pending = f.pending[n:]
+	return batch, nil
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestExpiredBefore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	assert.Equal(t, now.Add(-time.Hour), ExpiredBefore(now, time.Hour))
+	assert.True(t, ExpiredBefore(now, 0).IsZero())
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	queries := &fakeQueries{}
+	for i := 0; i < sweepBatchSize+3; i++ {
+		queries.pending = append(queries.pending, db.ExpiredLike{
+			ActorUserID:     "user1",
+			RecipientUserID: "user2",
+			LikedAt:         now.Add(-48*time.Hour + time.Duration(i)*time.Second),
+			ExpiredAt:       now,
+		})
+	}
+	publisher := &recordingPublisher{}
+
+	sweeper := NewSweeper(queries, publisher, 24*time.Hour, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	sweeper.now = func() time.Time { return now }
+
+	expired, err := sweeper.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, sweepBatchSize+3, expired)
+	require.Len(t, queries.calls, 2, "sweeps continue until a batch comes back short")
+	assert.Equal(t, now.Add(-24*time.Hour), queries.calls[0].ExpiredBefore)
+	assert.Equal(t, []time.Time{
+		now.Add(-48*time.Hour + (sweepBatchSize-1)*time.Second), // The newest like of the full batch
+		now.Add(-24 * time.Hour),
+	}, queries.swept, "the next sweep starts where this one stopped")
+
+	require.Len(t, publisher.events, sweepBatchSize+3)
+	assert.Equal(t, events.TypeLikeExpired, publisher.events[0].Type)
+	assert.Equal(t, "user1", publisher.events[0].ActorUserID)
+	assert.Equal(t, "user2", publisher.events[0].RecipientUserID)
+	assert.Equal(t, now.Add(-48*time.Hour).UTC().Format(time.RFC3339), publisher.events[0].Attributes["liked_at"])
+
+	// Nothing is swept when likes don't expire
+	sweeper = NewSweeper(queries, publisher, 0, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	expired, err = sweeper.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, expired)
+	assert.Len(t, queries.calls, 2)
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource []Row
+
+func (f fakeSource) Stream(ctx context.Context, filter Filter, fn func(Row) error) error {
+	for _, row := range f {
+		if filter.UserID != "" && row.ActorUserID != filter.UserID && row.RecipientUserID != filter.UserID {
+			continue
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	created = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows    = fakeSource{
+		{ID: 1, ActorUserID: "user1", RecipientUserID: "user2", Liked: true, SuperLike: true, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, ActorUserID: "user2", RecipientUserID: "user3", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+	}
+)
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "jsonl",
+			expected: `{"id":1,"actor_user_id":"user1","recipient_user_id":"user2","liked":true,"super_like":true,"created_at":"2024-03-01T12:00:00Z","updated_at":"2024-03-01T12:00:00Z"}
+{"id":2,"actor_user_id":"user2","recipient_user_id":"user3","liked":false,"super_like":false,"created_at":"2024-03-01T12:00:00Z","updated_at":"2024-03-01T13:00:00Z"}
+`,
+		},
+		{
+			format: "csv",
+			expected: `id,actor_user_id,recipient_user_id,liked,super_like,created_at,updated_at
+1,user1,user2,true,true,2024-03-01T12:00:00Z,2024-03-01T12:00:00Z
+2,user2,user3,false,false,2024-03-01T12:00:00Z,2024-03-01T13:00:00Z
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			manifest, err := Export(context.Background(), rows, Filter{}, &out, tt.format, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+
+			sum := sha256.Sum256(out.Bytes())
+			assert.Equal(t, int64(2), manifest.Rows)
+			assert.Equal(t, int64(out.Len()), manifest.Bytes)
+			assert.Equal(t, hex.EncodeToString(sum[:]), manifest.SHA256)
+			assert.Equal(t, "none", manifest.Compression)
+		})
+	}
+}
+
+func TestExport_Gzip(t *testing.T) {
+	var out bytes.Buffer
+	manifest, err := Export(context.Background(), rows, Filter{UserID: "user3"}, &out, "jsonl", true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), manifest.Rows)
+	assert.Equal(t, "gzip", manifest.Compression)
+	assert.Equal(t, "user3", manifest.UserID)
+
+	sum := sha256.Sum256(out.Bytes())
+	assert.Equal(t, hex.EncodeToString(sum[:]), manifest.SHA256, "checksum covers the compressed bytes")
+
+	gz, err := gzip.NewReader(&out)
+	require.NoError(t, err)
+	plain, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(plain), `{"id":2,`))
+}
+
+func TestExport_Parquet(t *testing.T) {
+	var out bytes.Buffer
+	manifest, err := Export(context.Background(), rows, Filter{}, &out, "parquet", false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), manifest.Rows)
+
+	read, err := parquet.Read[Row](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, []Row(rows), read)
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter(io.Discard, "xml", false)
+	assert.EqualError(t, err, `unknown format "xml", expected jsonl, csv or parquet`)
+}
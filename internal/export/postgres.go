@@ -0,0 +1,74 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fetchSize is how many rows are fetched from the cursor at a time
+const fetchSize = 1000
+
+const declareCursor = `
+DECLARE decisions_export NO SCROLL CURSOR FOR
+SELECT id, actor_user_id, recipient_user_id, liked, super_like, created_at, updated_at
+FROM decisions
+WHERE ($1::TIMESTAMPTZ IS NULL OR updated_at >= $1)
+  AND ($2::TIMESTAMPTZ IS NULL OR updated_at < $2)
+  AND ($3::TEXT IS NULL OR actor_user_id = $3 OR recipient_user_id = $3)
+ORDER BY id`
+
+// PostgresSource streams decisions through a server-side cursor in a
+// read-only, repeatable read transaction, so memory use stays flat and the
+// export sees a single snapshot however long it runs
+type PostgresSource struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSource(pool *pgxpool.Pool) *PostgresSource {
+	return &PostgresSource{pool: pool}
+}
+
+func (s *PostgresSource) Stream(ctx context.Context, filter Filter, fn func(Row) error) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var since, until, user any
+	if !filter.Since.IsZero() {
+		since = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		until = filter.Until
+	}
+	if filter.UserID != "" {
+		user = filter.UserID
+	}
+	if _, err := tx.Exec(ctx, declareCursor, since, until, user); err != nil {
+		return fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM decisions_export", fetchSize))
+		if err != nil {
+			return err
+		}
+
+		fetched := 0
+		var row Row
+		_, err = pgx.ForEachRow(rows, []any{&row.ID, &row.ActorUserID, &row.RecipientUserID, &row.Liked, &row.SuperLike, &row.CreatedAt, &row.UpdatedAt}, func() error {
+			fetched++
+			return fn(row)
+		})
+		if err != nil {
+			return err
+		}
+		if fetched < fetchSize {
+			return tx.Commit(ctx)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{"jsonl", "csv", "parquet"}
+
+// rowGroupSize bounds how many rows a Parquet writer buffers before writing them out
+const rowGroupSize = 100_000
+
+// Writer encodes rows in an export format. Close must be called to flush
+// buffered rows; it doesn't close the underlying writer.
+type Writer interface {
+	Write(row Row) error
+	Close() error
+}
+
+// NewWriter returns a writer for the jsonl, csv or parquet format. compress
+// gzips JSON lines and CSV output, and selects gzip over snappy for Parquet
+// column chunks, as Parquet files are compressed internally.
+func NewWriter(out io.Writer, format string, compress bool) (Writer, error) {
+	if format == "parquet" {
+		codec := parquet.Compression(&parquet.Snappy)
+		if compress {
+			codec = parquet.Compression(&parquet.Gzip)
+		}
+		return &parquetWriter{w: parquet.NewGenericWriter[Row](out, codec)}, nil
+	}
+
+	s := &stream{buf: bufio.NewWriter(out)}
+	s.out = s.buf
+	if compress {
+		s.gz = gzip.NewWriter(s.buf)
+		s.out = s.gz
+	}
+
+	switch format {
+	case "jsonl":
+		return &jsonlWriter{stream: s, enc: json.NewEncoder(s.out)}, nil
+	case "csv":
+		w := &csvWriter{stream: s, csv: csv.NewWriter(s.out)}
+		if err := w.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return w, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected jsonl, csv or parquet", format)
+	}
+}
+
+// stream buffers, and optionally gzips, text formats
+type stream struct {
+	buf *bufio.Writer
+	gz  *gzip.Writer
+	out io.Writer
+}
+
+func (s *stream) close() error {
+	if s.gz != nil {
+		if err := s.gz.Close(); err != nil {
+			return err
+		}
+	}
+	return s.buf.Flush()
+}
+
+type jsonlWriter struct {
+	*stream
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(row Row) error {
+	return w.enc.Encode(row)
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.close()
+}
+
+var csvHeader = []string{"id", "actor_user_id", "recipient_user_id", "liked", "super_like", "created_at", "updated_at"}
+
+type csvWriter struct {
+	*stream
+	csv    *csv.Writer
+	record [7]string
+}
+
+func (w *csvWriter) Write(row Row) error {
+	w.record = [7]string{
+		strconv.FormatInt(row.ID, 10),
+		row.ActorUserID,
+		row.RecipientUserID,
+		strconv.FormatBool(row.Liked),
+		strconv.FormatBool(row.SuperLike),
+		row.CreatedAt.UTC().Format(time.RFC3339Nano),
+		row.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	return w.csv.Write(w.record[:])
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.close()
+}
+
+type parquetWriter struct {
+	w       *parquet.GenericWriter[Row]
+	pending int
+}
+
+func (w *parquetWriter) Write(row Row) error {
+	if _, err := w.w.Write([]Row{row}); err != nil {
+		return err
+	}
+	w.pending++
+	if w.pending == rowGroupSize {
+		w.pending = 0
+		return w.w.Flush()
+	}
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	return w.w.Close()
+}
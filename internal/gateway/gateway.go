@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+// forwardedHeaders are HTTP headers passed to the gRPC service as metadata,
+// in addition to Authorization which the gateway always forwards
+var forwardedHeaders = map[string]string{
+	"X-Request-Id": "x-request-id",
+}
+
+// returnedHeaders are gRPC response headers passed back to HTTP clients
+var returnedHeaders = map[string]string{
+	"x-request-id": "X-Request-Id",
+	"retry-after":  "Retry-After",
+}
+
+// New returns an HTTP handler serving the REST/JSON mapping of the
+// ExploreService, as annotated in the proto, by calling the service over conn.
+// gRPC status codes are mapped to HTTP statuses by runtime.HTTPStatusFromCode,
+// e.g. INVALID_ARGUMENT to 400 and RESOURCE_EXHAUSTED to 429.
+func New(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(matchIncomingHeader),
+		runtime.WithOutgoingHeaderMatcher(matchOutgoingHeader),
+	)
+	if err := pb.RegisterExploreServiceHandler(ctx, mux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register gateway: %w", err)
+	}
+	return mux, nil
+}
+
+func matchIncomingHeader(key string) (string, bool) {
+	if name, ok := forwardedHeaders[textproto.CanonicalMIMEHeaderKey(key)]; ok {
+		return name, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func matchOutgoingHeader(key string) (string, bool) {
+	if name, ok := returnedHeaders[strings.ToLower(key)]; ok {
+		return name, true
+	}
+	return "", false
+}
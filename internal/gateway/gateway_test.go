@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+type fakeService struct {
+	pb.UnimplementedExploreServiceServer
+}
+
+func (fakeService) CountLikedYou(ctx context.Context, req *pb.CountLikedYouRequest) (*pb.CountLikedYouResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	grpc.SetHeader(ctx, metadata.MD{"x-request-id": md.Get("x-request-id")})
+	return &pb.CountLikedYouResponse{Count: 5}, nil
+}
+
+func (fakeService) PutDecision(ctx context.Context, req *pb.PutDecisionRequest) (*pb.PutDecisionResponse, error) {
+	if req.ActorUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "both actor_user_id and recipient_user_id are required")
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", "3"))
+	return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+func newTestGateway(t *testing.T) http.Handler {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterExploreServiceServer(server, fakeService{})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///test",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	handler, err := New(context.Background(), conn)
+	require.NoError(t, err)
+	return handler
+}
+
+func TestGateway(t *testing.T) {
+	handler := newTestGateway(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/user2/likes/count", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Request-Id", "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "req-1", rec.Header().Get("X-Request-Id"))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "5", body["count"])
+}
+
+func TestGateway_StatusMapping(t *testing.T) {
+	handler := newTestGateway(t)
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{
+			name:       "unauthenticated",
+			req:        httptest.NewRequest(http.MethodGet, "/v1/users/user2/likes/count", nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid argument",
+			req:        httptest.NewRequest(http.MethodPost, "/v1/decisions", strings.NewReader(`{"recipientUserId": "user2"}`)),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "resource exhausted",
+			req:        httptest.NewRequest(http.MethodPost, "/v1/decisions", strings.NewReader(`{"actor_user_id": "user1", "recipient_user_id": "user2"}`)),
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "unimplemented",
+			req:        httptest.NewRequest(http.MethodGet, "/v1/users/user2/quota", nil),
+			wantStatus: http.StatusNotImplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "3", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check reports whether a dependency is ready, returning nil when it is
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) error
+}
+
+// Prober periodically runs readiness checks and reports the result through a
+// gRPC health server, so Kubernetes readiness tracks dependency outages
+type Prober struct {
+	server   *health.Server
+	services []string
+	checks   []Check
+	interval time.Duration
+	timeout  time.Duration
+	logger   *slog.Logger
+}
+
+// NewProber creates a prober that updates the status of the given services,
+// plus the overall server status (""), after each round of checks
+func NewProber(server *health.Server, services []string, interval time.Duration, logger *slog.Logger, checks ...Check) *Prober {
+	return &Prober{
+		server:   server,
+		services: append([]string{""}, services...),
+		checks:   checks,
+		interval: interval,
+		timeout:  interval / 2,
+		logger:   logger,
+	}
+}
+
+// Run probes immediately and then every interval until ctx is cancelled
+func (p *Prober) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.Probe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Probe runs every check once and sets the serving status accordingly.
+// Once the health server has been shut down, status updates are ignored.
+func (p *Prober) Probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	for _, check := range p.checks {
+		if err := check.Probe(ctx); err != nil {
+			p.logger.WarnContext(ctx, "readiness check failed", "check", check.Name, "error", err)
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+
+	for _, service := range p.services {
+		p.server.SetServingStatus(service, status)
+	}
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func servingStatus(t *testing.T, hs *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestProber(t *testing.T) {
+	hs := health.NewServer()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var dbErr error
+	p := NewProber(hs, []string{"explore.ExploreService"}, time.Second, logger,
+		Check{Name: "database", Probe: func(ctx context.Context) error { return dbErr }},
+	)
+
+	p.Probe(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, hs, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, hs, "explore.ExploreService"))
+
+	dbErr = errors.New("connection refused")
+	p.Probe(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, hs, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, hs, "explore.ExploreService"))
+
+	// A recovered dependency must not flip the status back once shutdown has begun
+	dbErr = nil
+	hs.Shutdown()
+	p.Probe(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, hs, ""))
+}
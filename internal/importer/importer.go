@@ -0,0 +1,182 @@
+// Package importer bulk loads historical decisions, such as swipe history from
+// a legacy system, into the decisions table
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// DefaultBatchSize is how many records are merged and checkpointed per transaction
+const DefaultBatchSize = 5000
+
+// Store merges imported decisions and tracks how far each import has got
+type Store interface {
+	// Checkpoint returns how many input records of the named import have been committed
+	Checkpoint(ctx context.Context, name string) (int64, error)
+	// Merge upserts the batch with last-write-wins semantics and moves the named
+	// import's checkpoint to position in the same transaction
+	Merge(ctx context.Context, name string, batch []Record, position int64) (MergeResult, error)
+}
+
+// MergeResult counts what a merge did with the records of a batch
+type MergeResult struct {
+	Inserted int64 // New decisions
+	Updated  int64 // Existing decisions replaced by a later record or given an earlier created_at
+	Stale    int64 // Records that changed nothing, being older than the stored decision or a later record in the batch
+}
+
+func (r *MergeResult) add(other MergeResult) {
+	r.Inserted += other.Inserted
+	r.Updated += other.Updated
+	r.Stale += other.Stale
+}
+
+// Summary describes an import or dry run
+type Summary struct {
+	MergeResult
+	Records int64 // Input records read, including invalid and resumed ones
+	Resumed int64 // Records skipped because an earlier run committed them
+	Invalid int64 // Records skipped because they couldn't be parsed or validated
+}
+
+// Importer streams records into a store in checkpointed batches
+type Importer struct {
+	store       Store
+	batchSize   int
+	skipInvalid bool
+	logger      *slog.Logger
+}
+
+// NewImporter creates an importer merging batchSize records per transaction.
+// Invalid records abort the import unless skipInvalid is set, in which case
+// they're logged and skipped.
+func NewImporter(store Store, batchSize int, skipInvalid bool, logger *slog.Logger) *Importer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Importer{
+		store:       store,
+		batchSize:   batchSize,
+		skipInvalid: skipInvalid,
+		logger:      logger,
+	}
+}
+
+// Run imports every record from the reader under the given name. If an earlier
+// run with the same name was interrupted, the records it committed are skipped,
+// so the same input must be given to resume.
+func (i *Importer) Run(ctx context.Context, name string, reader Reader) (Summary, error) {
+	var summary Summary
+
+	start, err := i.store.Checkpoint(ctx, name)
+	if err != nil {
+		return summary, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if start > 0 {
+		i.logger.InfoContext(ctx, "resuming import", "name", name, "position", start)
+	}
+
+	committed := start
+	batch := make([]Record, 0, i.batchSize)
+	began := time.Now()
+
+	flush := func() error {
+		result, err := i.store.Merge(ctx, name, batch, summary.Records)
+		if err != nil {
+			return fmt.Errorf("failed to merge records up to %d: %w", summary.Records, err)
+		}
+		summary.add(result)
+		committed = summary.Records
+		batch = batch[:0]
+
+		i.logger.InfoContext(ctx, "import progress",
+			"position", summary.Records,
+			"inserted", summary.Inserted,
+			"updated", summary.Updated,
+			"stale", summary.Stale,
+			"invalid", summary.Invalid,
+			"elapsed", time.Since(began).Round(time.Millisecond),
+		)
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var recordErr *RecordError
+		if err != nil && !errors.As(err, &recordErr) {
+			return summary, err
+		}
+		summary.Records++
+
+		if summary.Records <= start {
+			summary.Resumed++
+			continue
+		}
+
+		if err == nil {
+			if err := record.Validate(); err != nil {
+				recordErr = &RecordError{Line: record.Line, Err: err}
+			}
+		}
+		if recordErr != nil {
+			if !i.skipInvalid {
+				return summary, recordErr
+			}
+			summary.Invalid++
+			i.logger.WarnContext(ctx, "skipping invalid record", "error", recordErr)
+			continue
+		}
+
+		batch = append(batch, record)
+		if len(batch) == i.batchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+
+	if summary.Records < start {
+		return summary, fmt.Errorf("input has %d records but the checkpoint is at %d, was a different input given?", summary.Records, start)
+	}
+	if summary.Records > committed {
+		if err := flush(); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// Check reads and validates every record without writing anything, reporting
+// each invalid record through report
+func Check(reader Reader, report func(*RecordError)) (Summary, error) {
+	var summary Summary
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return summary, nil
+		}
+		var recordErr *RecordError
+		if err != nil && !errors.As(err, &recordErr) {
+			return summary, err
+		}
+		summary.Records++
+
+		if err == nil {
+			if err := record.Validate(); err != nil {
+				recordErr = &RecordError{Line: record.Line, Err: err}
+			}
+		}
+		if recordErr != nil {
+			summary.Invalid++
+			report(recordErr)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleCSV = `actor_user_id,recipient_user_id,liked,super_like,created_at,updated_at
+user1,user2,true,false,2023-01-01T10:00:00Z,
+user2,user1,false,,1672567200,2023-01-02T10:00:00Z
+user3,user1,true,true,2023-01-03T10:00:00Z,2023-01-04T10:00:00Z
+`
+
+func TestCSVReader(t *testing.T) {
+	reader, err := NewCSVReader(strings.NewReader(sampleCSV))
+	require.NoError(t, err)
+
+	var records []Record
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+
+	day := func(d int) time.Time { return time.Date(2023, 1, d, 10, 0, 0, 0, time.UTC) }
+	assert.Equal(t, []Record{
+		{Line: 2, ActorUserID: "user1", RecipientUserID: "user2", Liked: true, CreatedAt: day(1), UpdatedAt: day(1)},
+		{Line: 3, ActorUserID: "user2", RecipientUserID: "user1", CreatedAt: day(1), UpdatedAt: day(2)},
+		{Line: 4, ActorUserID: "user3", RecipientUserID: "user1", Liked: true, SuperLike: true, CreatedAt: day(3), UpdatedAt: day(4)},
+	}, records)
+
+	_, err = NewCSVReader(strings.NewReader("actor_user_id,recipient_user_id,liked\n"))
+	assert.EqualError(t, err, "CSV header is missing the created_at column")
+}
+
+func TestJSONLReader(t *testing.T) {
+	input := `{"actor_user_id": "user1", "recipient_user_id": "user2", "liked": true, "created_at": "2023-01-01T10:00:00Z"}
+
+{"actor_user_id": "user2", "recipient_user_id": "user1", "liked": "maybe", "created_at": 1672567200}
+{"actor_user_id": "user3", "recipient_user_id": "user1", "liked": false, "created_at": 1672567200, "legacy_id": 7}
+not json
+`
+	reader := NewJSONLReader(strings.NewReader(input))
+
+	record, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, Record{Line: 1, ActorUserID: "user1", RecipientUserID: "user2", Liked: true,
+		CreatedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)}, record)
+
+	_, err = reader.Read()
+	var recordErr *RecordError
+	require.ErrorAs(t, err, &recordErr)
+	assert.EqualError(t, err, `line 3: invalid liked "maybe"`)
+
+	// Unknown fields are ignored
+	record, err = reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "user3", record.ActorUserID)
+
+	_, err = reader.Read()
+	require.ErrorAs(t, err, &recordErr)
+	assert.Equal(t, int64(5), recordErr.Line)
+
+	_, err = reader.Read()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestValidate(t *testing.T) {
+	created := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	valid := Record{ActorUserID: "user1", RecipientUserID: "user2", Liked: true, CreatedAt: created, UpdatedAt: created}
+
+	tests := []struct {
+		name   string
+		modify func(r *Record)
+		err    string
+	}{
+		{name: "valid", modify: func(r *Record) {}},
+		{name: "missing actor", modify: func(r *Record) { r.ActorUserID = "" }, err: "actor_user_id and recipient_user_id are required"},
+		{name: "self decision", modify: func(r *Record) { r.RecipientUserID = "user1" }, err: "actor_user_id and recipient_user_id must differ"},
+		{name: "super pass", modify: func(r *Record) { r.Liked, r.SuperLike = false, true }, err: "super_like requires liked"},
+		{name: "missing created_at", modify: func(r *Record) { r.CreatedAt = time.Time{} }, err: "created_at is required"},
+		{name: "updated before created", modify: func(r *Record) { r.UpdatedAt = created.Add(-time.Hour) }, err: "updated_at is before created_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := valid
+			tt.modify(&record)
+			err := record.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+// fakeStore records merged batches and fails once a given position is reached
+type fakeStore struct {
+	checkpoint int64
+	batches    [][]string
+	failAt     int64
+}
+
+func (f *fakeStore) Checkpoint(ctx context.Context, name string) (int64, error) {
+	return f.checkpoint, nil
+}
+
+func (f *fakeStore) Merge(ctx context.Context, name string, batch []Record, position int64) (MergeResult, error) {
+	if f.failAt > 0 && position >= f.failAt {
+		return MergeResult{}, errors.New("connection reset")
+	}
+	var actors []string
+	for _, r := range batch {
+		actors = append(actors, r.ActorUserID)
+	}
+	f.batches = append(f.batches, actors)
+	f.checkpoint = position
+	return MergeResult{Inserted: int64(len(batch))}, nil
+}
+
+func decisionsCSV(actors ...string) string {
+	lines := []string{"actor_user_id,recipient_user_id,liked,created_at"}
+	for _, actor := range actors {
+		lines = append(lines, actor+",target,true,2023-01-01T10:00:00Z")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestImporterRun(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	input := decisionsCSV("a", "b", "c", "d", "e")
+
+	// Interrupted after the first batch
+	store := &fakeStore{failAt: 4}
+	reader, err := NewCSVReader(strings.NewReader(input))
+	require.NoError(t, err)
+	_, err = NewImporter(store, 2, false, logger).Run(context.Background(), "legacy", reader)
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, int64(2), store.checkpoint)
+
+	// Resumes after the committed records
+	store.failAt = 0
+	reader, err = NewCSVReader(strings.NewReader(input))
+	require.NoError(t, err)
+	summary, err := NewImporter(store, 2, false, logger).Run(context.Background(), "legacy", reader)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, store.batches)
+	assert.Equal(t, Summary{MergeResult: MergeResult{Inserted: 3}, Records: 5, Resumed: 2}, summary)
+	assert.Equal(t, int64(5), store.checkpoint)
+}
+
+func TestImporterRun_Invalid(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	input := decisionsCSV("a", "target", "c")
+
+	store := &fakeStore{}
+	reader, err := NewCSVReader(strings.NewReader(input))
+	require.NoError(t, err)
+	_, err = NewImporter(store, 10, false, logger).Run(context.Background(), "legacy", reader)
+	assert.EqualError(t, err, "line 3: actor_user_id and recipient_user_id must differ")
+	assert.Empty(t, store.batches)
+
+	reader, err = NewCSVReader(strings.NewReader(input))
+	require.NoError(t, err)
+	summary, err := NewImporter(store, 10, true, logger).Run(context.Background(), "legacy", reader)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "c"}}, store.batches)
+	assert.Equal(t, int64(1), summary.Invalid)
+	assert.Equal(t, int64(3), store.checkpoint)
+
+	// A checkpoint past the end of the input means the input changed
+	reader, err = NewCSVReader(strings.NewReader(decisionsCSV("a")))
+	require.NoError(t, err)
+	_, err = NewImporter(store, 10, true, logger).Run(context.Background(), "legacy", reader)
+	assert.ErrorContains(t, err, "checkpoint is at 3")
+}
+
+func TestCheck(t *testing.T) {
+	reader, err := NewCSVReader(strings.NewReader(decisionsCSV("a", "target", "c") + "d,target,nope,2023-01-01T10:00:00Z\n"))
+	require.NoError(t, err)
+
+	var reported []string
+	summary, err := Check(reader, func(err *RecordError) {
+		reported = append(reported, err.Error())
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Records: 4, Invalid: 2}, summary)
+	assert.Equal(t, []string{
+		"line 3: actor_user_id and recipient_user_id must differ",
+		`line 5: invalid liked "nope"`,
+	}, reported)
+}
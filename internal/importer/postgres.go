@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"muzz-explore-service/internal/db"
+)
+
+const createStagingTable = `
+CREATE TEMP TABLE decisions_staging (
+    seq BIGINT NOT NULL,
+    actor_user_id TEXT NOT NULL,
+    recipient_user_id TEXT NOT NULL,
+    liked BOOLEAN NOT NULL,
+    super_like BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+) ON COMMIT DROP`
+
+// mergeStaged upserts the latest staged record for each pair, keeping the
+// stored decision if it changed more recently. Decisions take the earliest
+// created_at of the stored and staged records, even when the stored decision
+// is kept. Returns one row per written decision, true if it was inserted.
+const mergeStaged = `
+INSERT INTO decisions AS d (actor_user_id, recipient_user_id, liked, super_like, created_at, updated_at)
+SELECT DISTINCT ON (actor_user_id, recipient_user_id)
+    actor_user_id,
+    recipient_user_id,
+    liked,
+    super_like,
+    MIN(created_at) OVER (PARTITION BY actor_user_id, recipient_user_id),
+    updated_at
+FROM decisions_staging
+ORDER BY actor_user_id, recipient_user_id, updated_at DESC, seq DESC
+ON CONFLICT (actor_user_id, recipient_user_id)
+    DO UPDATE SET
+        liked = CASE WHEN EXCLUDED.updated_at > d.updated_at THEN EXCLUDED.liked ELSE d.liked END,
+        super_like = CASE WHEN EXCLUDED.updated_at > d.updated_at THEN EXCLUDED.super_like ELSE d.super_like END,
+        updated_at = GREATEST(d.updated_at, EXCLUDED.updated_at),
+        created_at = LEAST(d.created_at, EXCLUDED.created_at)
+    WHERE EXCLUDED.updated_at > d.updated_at OR EXCLUDED.created_at < d.created_at
+RETURNING xmax = 0`
+
+var stagingColumns = []string{"seq", "actor_user_id", "recipient_user_id", "liked", "super_like", "created_at", "updated_at"}
+
+// PostgresStore merges batches through a temporary staging table loaded with COPY
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+func (s *PostgresStore) Checkpoint(ctx context.Context, name string) (int64, error) {
+	position, err := db.New(s.pool).GetImportCheckpoint(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return position, err
+}
+
+func (s *PostgresStore) Merge(ctx context.Context, name string, batch []Record, position int64) (MergeResult, error) {
+	var result MergeResult
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback(ctx)
+
+	if len(batch) > 0 {
+		if _, err := tx.Exec(ctx, createStagingTable); err != nil {
+			return result, fmt.Errorf("failed to create staging table: %w", err)
+		}
+
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"decisions_staging"}, stagingColumns,
+			pgx.CopyFromSlice(len(batch), func(i int) ([]any, error) {
+				r := batch[i]
+				return []any{int64(i), r.ActorUserID, r.RecipientUserID, r.Liked, r.SuperLike, r.CreatedAt, r.UpdatedAt}, nil
+			}))
+		if err != nil {
+			return result, fmt.Errorf("failed to copy records: %w", err)
+		}
+
+		rows, err := tx.Query(ctx, mergeStaged)
+		if err != nil {
+			return result, fmt.Errorf("failed to merge records: %w", err)
+		}
+		inserted, err := pgx.CollectRows(rows, pgx.RowTo[bool])
+		if err != nil {
+			return result, fmt.Errorf("failed to merge records: %w", err)
+		}
+		for _, isInsert := range inserted {
+			if isInsert {
+				result.Inserted++
+			} else {
+				result.Updated++
+			}
+		}
+		result.Stale = int64(len(batch)) - result.Inserted - result.Updated
+
+		// Imported likes can be older than the expiry sweep's watermark, so
+		// the next sweep goes back far enough to check them
+		if likedBefore, ok := earliestLike(batch); ok {
+			if err := db.New(tx).RewindLikeExpirySweep(ctx, likedBefore); err != nil {
+				return result, fmt.Errorf("failed to rewind like expiry sweep: %w", err)
+			}
+		}
+	}
+
+	if err := db.New(tx).SaveImportCheckpoint(ctx, db.SaveImportCheckpointParams{Name: name, Position: position}); err != nil {
+		return result, fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return MergeResult{}, err
+	}
+	return result, nil
+}
+
+// earliestLike returns the earliest creation time of the liked records
+func earliestLike(batch []Record) (time.Time, bool) {
+	var earliest time.Time
+	for _, r := range batch {
+		if r.Liked && (earliest.IsZero() || r.CreatedAt.Before(earliest)) {
+			earliest = r.CreatedAt
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is one historical decision to import
+type Record struct {
+	Line            int64 // Line of the input the record starts on, for error messages
+	ActorUserID     string
+	RecipientUserID string
+	Liked           bool
+	SuperLike       bool
+	CreatedAt       time.Time // When the actor first decided on the recipient
+	UpdatedAt       time.Time // When the decision last changed, defaults to CreatedAt
+}
+
+// Validate checks the record could have been made through PutDecision
+func (r Record) Validate() error {
+	switch {
+	case r.ActorUserID == "" || r.RecipientUserID == "":
+		return errors.New("actor_user_id and recipient_user_id are required")
+	case r.ActorUserID == r.RecipientUserID:
+		return errors.New("actor_user_id and recipient_user_id must differ")
+	case r.SuperLike && !r.Liked:
+		return errors.New("super_like requires liked")
+	case r.CreatedAt.IsZero():
+		return errors.New("created_at is required")
+	case r.UpdatedAt.Before(r.CreatedAt):
+		return errors.New("updated_at is before created_at")
+	}
+	return nil
+}
+
+// RecordError reports an input record that can't be imported. Reading can
+// continue past it.
+type RecordError struct {
+	Line int64
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads records from an input, returning io.EOF once it is exhausted.
+// Records that can't be parsed are returned as a *RecordError.
+type Reader interface {
+	Read() (Record, error)
+}
+
+// NewReader returns a reader for the csv or jsonl format
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case "csv":
+		return NewCSVReader(r)
+	case "jsonl":
+		return NewJSONLReader(r), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected csv or jsonl", format)
+	}
+}
+
+// Columns of the CSV format, in any order. super_like and updated_at are optional.
+var (
+	requiredColumns = []string{"actor_user_id", "recipient_user_id", "liked", "created_at"}
+	optionalColumns = []string{"super_like", "updated_at"}
+)
+
+// CSVReader reads records from CSV with a header row naming the columns
+type CSVReader struct {
+	csv     *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVReader reads the header row and returns a reader for the rest of the input
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", name)
+		}
+	}
+
+	return &CSVReader{csv: reader, columns: columns}, nil
+}
+
+func (r *CSVReader) Read() (Record, error) {
+	fields, err := r.csv.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{}, &RecordError{Line: int64(parseErr.StartLine), Err: parseErr.Err}
+		}
+		return Record{}, err
+	}
+	line, _ := r.csv.FieldPos(0)
+
+	field := func(name string) string {
+		i, ok := r.columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	record, err := parseRecord(field("actor_user_id"), field("recipient_user_id"), field("liked"), field("super_like"), field("created_at"), field("updated_at"))
+	if err != nil {
+		return Record{}, &RecordError{Line: int64(line), Err: err}
+	}
+	record.Line = int64(line)
+	return record, nil
+}
+
+// maxLineSize bounds the length of a JSON-lines record
+const maxLineSize = 1 << 20
+
+// JSONLReader reads records from JSON lines, one object per line with the
+// same fields as the CSV columns. Blank lines are ignored.
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	line    int64
+}
+
+// NewJSONLReader returns a reader for JSON lines
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &JSONLReader{scanner: scanner}
+}
+
+type jsonRecord struct {
+	ActorUserID     string          `json:"actor_user_id"`
+	RecipientUserID string          `json:"recipient_user_id"`
+	Liked           json.RawMessage `json:"liked"`
+	SuperLike       json.RawMessage `json:"super_like"`
+	CreatedAt       json.RawMessage `json:"created_at"`
+	UpdatedAt       json.RawMessage `json:"updated_at"`
+}
+
+func (r *JSONLReader) Read() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var raw jsonRecord
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return Record{}, &RecordError{Line: r.line, Err: err}
+		}
+		record, err := parseRecord(raw.ActorUserID, raw.RecipientUserID, jsonScalar(raw.Liked), jsonScalar(raw.SuperLike), jsonScalar(raw.CreatedAt), jsonScalar(raw.UpdatedAt))
+		if err != nil {
+			return Record{}, &RecordError{Line: r.line, Err: err}
+		}
+		record.Line = r.line
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// jsonScalar returns a JSON string's contents, or any other value's literal text
+func jsonScalar(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+func parseRecord(actor, recipient, liked, superLike, createdAt, updatedAt string) (Record, error) {
+	record := Record{ActorUserID: actor, RecipientUserID: recipient}
+
+	var err error
+	if liked == "" {
+		return Record{}, errors.New("liked is required")
+	}
+	if record.Liked, err = strconv.ParseBool(liked); err != nil {
+		return Record{}, fmt.Errorf("invalid liked %q", liked)
+	}
+	if superLike != "" {
+		if record.SuperLike, err = strconv.ParseBool(superLike); err != nil {
+			return Record{}, fmt.Errorf("invalid super_like %q", superLike)
+		}
+	}
+	if createdAt != "" {
+		if record.CreatedAt, err = parseTime(createdAt); err != nil {
+			return Record{}, fmt.Errorf("invalid created_at %q", createdAt)
+		}
+	}
+	record.UpdatedAt = record.CreatedAt
+	if updatedAt != "" {
+		if record.UpdatedAt, err = parseTime(updatedAt); err != nil {
+			return Record{}, fmt.Errorf("invalid updated_at %q", updatedAt)
+		}
+	}
+
+	return record, nil
+}
+
+// parseTime parses an RFC 3339 time or Unix timestamp in seconds
+func parseTime(value string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
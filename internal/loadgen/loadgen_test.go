@@ -0,0 +1,130 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix("put=60, list=20,count=20")
+	require.NoError(t, err)
+	assert.Equal(t, Mix{OpPutDecision: 60, OpListLikedYou: 20, OpCountLikedYou: 20}, mix)
+	assert.Equal(t, "put=60,list=20,count=20", mix.String())
+
+	for _, invalid := range []string{"put", "swipe=1", "put=-1", "put=0,list=0"} {
+		_, err := ParseMix(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestWorkload(t *testing.T) {
+	w, err := NewWorkload(1000, 1.2, 0.25, Mix{OpPutDecision: 3, OpCountLikedYou: 1}, 42)
+	require.NoError(t, err)
+
+	const n = 20000
+	ops := map[Op]int{}
+	recipients := map[string]int{}
+	likes := 0
+	for range n {
+		call := w.Next()
+		ops[call.Op]++
+		recipients[call.Recipient]++
+		if call.Op == OpPutDecision {
+			assert.NotEqual(t, call.Actor, call.Recipient)
+			if call.Liked {
+				likes++
+			}
+		}
+	}
+
+	assert.InDelta(t, 0.75, float64(ops[OpPutDecision])/n, 0.02)
+	assert.InDelta(t, 0.25, float64(ops[OpCountLikedYou])/n, 0.02)
+	assert.InDelta(t, 0.25, float64(likes)/float64(ops[OpPutDecision]), 0.02)
+	// Popularity is skewed towards the first users
+	assert.Greater(t, recipients[w.User(0)], recipients[w.User(10)])
+	assert.Greater(t, recipients[w.User(10)], recipients[w.User(500)])
+
+	// The same seed generates the same calls
+	a, _ := NewWorkload(100, 1.5, 0.5, DefaultMix, 7)
+	b, _ := NewWorkload(100, 1.5, 0.5, DefaultMix, 7)
+	for range 100 {
+		assert.Equal(t, a.Next(), b.Next())
+	}
+
+	_, err = NewWorkload(100, 1, 0.5, DefaultMix, 1)
+	assert.EqualError(t, err, "zipf skew must be greater than 1, got 1")
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 0.5))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 0.99))
+	assert.Equal(t, time.Millisecond, percentile(latencies[:1], 0.99))
+	assert.Equal(t, time.Duration(0), percentile(nil, 0.5))
+}
+
+// fakeClient counts calls, failing every CountLikedYou
+type fakeClient struct {
+	pb.ExploreServiceClient
+	puts atomic.Int64
+}
+
+func (f *fakeClient) PutDecision(ctx context.Context, in *pb.PutDecisionRequest, opts ...grpc.CallOption) (*pb.PutDecisionResponse, error) {
+	f.puts.Add(1)
+	return &pb.PutDecisionResponse{}, nil
+}
+
+func (f *fakeClient) ListLikedYou(ctx context.Context, in *pb.ListLikedYouRequest, opts ...grpc.CallOption) (*pb.ListLikedYouResponse, error) {
+	return &pb.ListLikedYouResponse{}, nil
+}
+
+func (f *fakeClient) CountLikedYou(ctx context.Context, in *pb.CountLikedYouRequest, opts ...grpc.CallOption) (*pb.CountLikedYouResponse, error) {
+	return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+func TestRun(t *testing.T) {
+	client := &fakeClient{}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	report, err := Run(ctx, client, Config{
+		Users:       100,
+		Skew:        1.1,
+		LikeRatio:   0.5,
+		Mix:         Mix{OpPutDecision: 2, OpListLikedYou: 1, OpCountLikedYou: 1},
+		Concurrency: 4,
+		Rate:        500,
+		Seed:        1,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Ops, 3)
+
+	calls, errors := report.Total()
+	assert.InDelta(t, 100, calls, 60, "the target rate limits calls")
+	assert.Equal(t, client.puts.Load(), report.Ops[0].Calls)
+
+	count := report.Ops[2]
+	assert.Equal(t, OpCountLikedYou, count.Op)
+	assert.Equal(t, count.Calls, errors)
+	assert.Equal(t, 1.0, count.ErrorRate())
+	assert.Equal(t, map[codes.Code]int64{codes.ResourceExhausted: count.Calls}, count.ErrorsByCode)
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "PutDecision")
+	assert.Contains(t, out.String(), "CountLikedYou errors: ResourceExhausted=")
+}
@@ -0,0 +1,178 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Recorder collects the outcome of every call. It's safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	ops map[Op]*opResults
+}
+
+type opResults struct {
+	latencies []time.Duration
+	errors    map[codes.Code]int64
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{ops: make(map[Op]*opResults)}
+}
+
+// Record adds the latency and result of a call
+func (r *Recorder) Record(op Op, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results, ok := r.ops[op]
+	if !ok {
+		results = &opResults{errors: make(map[codes.Code]int64)}
+		r.ops[op] = results
+	}
+	results.latencies = append(results.latencies, latency)
+	if err != nil {
+		results.errors[status.Code(err)]++
+	}
+}
+
+// OpReport summarises the calls made for one op
+type OpReport struct {
+	Op           Op
+	Calls        int64
+	Errors       int64
+	ErrorsByCode map[codes.Code]int64
+	P50          time.Duration
+	P90          time.Duration
+	P99          time.Duration
+	Max          time.Duration
+}
+
+// ErrorRate returns the fraction of calls that failed
+func (o OpReport) ErrorRate() float64 {
+	if o.Calls == 0 {
+		return 0
+	}
+	return float64(o.Errors) / float64(o.Calls)
+}
+
+// Report summarises a load test
+type Report struct {
+	Elapsed time.Duration
+	Ops     []OpReport
+}
+
+// Report summarises the calls recorded over elapsed
+func (r *Recorder) Report(elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{Elapsed: elapsed}
+	for _, op := range Ops {
+		results, ok := r.ops[op]
+		if !ok {
+			continue
+		}
+
+		latencies := slices.Clone(results.latencies)
+		slices.Sort(latencies)
+		opReport := OpReport{
+			Op:           op,
+			Calls:        int64(len(latencies)),
+			ErrorsByCode: make(map[codes.Code]int64, len(results.errors)),
+			P50:          percentile(latencies, 0.50),
+			P90:          percentile(latencies, 0.90),
+			P99:          percentile(latencies, 0.99),
+			Max:          latencies[len(latencies)-1],
+		}
+		for code, n := range results.errors {
+			opReport.Errors += n
+			opReport.ErrorsByCode[code] = n
+		}
+		report.Ops = append(report.Ops, opReport)
+	}
+	return report
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// Total returns how many calls were made and how many failed across all ops
+func (r Report) Total() (calls, errors int64) {
+	for _, op := range r.Ops {
+		calls += op.Calls
+		errors += op.Errors
+	}
+	return calls, errors
+}
+
+// Write prints the report as a table, with a row per RPC and a total
+func (r Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "RPC\tCALLS\tCALLS/S\tERRORS\tERROR %\tP50\tP90\tP99\tMAX\t")
+	for _, op := range r.Ops {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n",
+			op.Op.RPC(), op.Calls, r.rate(op.Calls), op.Errors, 100*op.ErrorRate(),
+			round(op.P50), round(op.P90), round(op.P99), round(op.Max))
+	}
+	calls, errors := r.Total()
+	errorRate := 0.0
+	if calls > 0 {
+		errorRate = 100 * float64(errors) / float64(calls)
+	}
+	fmt.Fprintf(tw, "total\t%d\t%.1f\t%d\t%.2f\t\t\t\t\t\n", calls, r.rate(calls), errors, errorRate)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, op := range r.Ops {
+		if op.Errors == 0 {
+			continue
+		}
+		codesSeen := make([]codes.Code, 0, len(op.ErrorsByCode))
+		for code := range op.ErrorsByCode {
+			codesSeen = append(codesSeen, code)
+		}
+		sort.Slice(codesSeen, func(i, j int) bool { return codesSeen[i] < codesSeen[j] })
+
+		parts := make([]string, len(codesSeen))
+		for i, code := range codesSeen {
+			parts[i] = fmt.Sprintf("%s=%d", code, op.ErrorsByCode[code])
+		}
+		fmt.Fprintf(w, "%s errors: %s\n", op.Op.RPC(), strings.Join(parts, " "))
+	}
+	return nil
+}
+
+func (r Report) rate(calls int64) float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(calls) / r.Elapsed.Seconds()
+}
+
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
@@ -0,0 +1,123 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+// Config describes the traffic to generate
+type Config struct {
+	Users       int     // Simulated user population
+	Skew        float64 // Zipf exponent of user popularity, greater than 1
+	LikeRatio   float64 // Fraction of decisions that are likes
+	Mix         Mix
+	Concurrency int     // Workers making calls in parallel
+	Rate        float64 // Target calls per second across all workers, 0 for as fast as possible
+	Seed        int64
+}
+
+// Run makes calls until the context is done and reports how they went.
+// The context's deadline sets the length of the test.
+func Run(ctx context.Context, client pb.ExploreServiceClient, cfg Config) (Report, error) {
+	if cfg.Concurrency <= 0 {
+		return Report{}, fmt.Errorf("concurrency must be positive, got %d", cfg.Concurrency)
+	}
+
+	workloads := make([]*Workload, cfg.Concurrency)
+	for i := range workloads {
+		w, err := NewWorkload(cfg.Users, cfg.Skew, cfg.LikeRatio, cfg.Mix, cfg.Seed+int64(i))
+		if err != nil {
+			return Report{}, err
+		}
+		workloads[i] = w
+	}
+
+	// With a target rate, workers wait for a token before each call
+	var tokens chan struct{}
+	if cfg.Rate > 0 {
+		tokens = make(chan struct{}, cfg.Concurrency)
+		go pace(ctx, tokens, cfg.Rate)
+	}
+
+	recorder := NewRecorder()
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for _, w := range workloads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if tokens != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-tokens:
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+
+				call := w.Next()
+				began := time.Now()
+				err := Do(ctx, client, call)
+				// Calls cut short by the end of the test aren't failures of the service
+				if err != nil && ctx.Err() != nil {
+					return
+				}
+				recorder.Record(call.Op, time.Since(began), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return recorder.Report(time.Since(start)), nil
+}
+
+// pace sends rate tokens per second until the context is done
+func pace(ctx context.Context, tokens chan<- struct{}, rate float64) {
+	interval := time.Duration(float64(time.Second) / rate)
+	ticker := time.NewTicker(max(interval, time.Microsecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			select {
+			case tokens <- struct{}{}:
+			default:
+				// Workers are saturated, drop the token rather than bursting later
+			}
+		}
+	}
+}
+
+// Do makes a single call
+func Do(ctx context.Context, client pb.ExploreServiceClient, call Call) error {
+	var err error
+	switch call.Op {
+	case OpPutDecision:
+		_, err = client.PutDecision(ctx, &pb.PutDecisionRequest{
+			ActorUserId:     call.Actor,
+			RecipientUserId: call.Recipient,
+			LikedRecipient:  call.Liked,
+		})
+	case OpListLikedYou:
+		_, err = client.ListLikedYou(ctx, &pb.ListLikedYouRequest{RecipientUserId: call.Recipient})
+	case OpListNewLikedYou:
+		_, err = client.ListNewLikedYou(ctx, &pb.ListLikedYouRequest{RecipientUserId: call.Recipient})
+	case OpCountLikedYou:
+		_, err = client.CountLikedYou(ctx, &pb.CountLikedYouRequest{RecipientUserId: call.Recipient})
+	default:
+		err = errors.New("unknown op")
+	}
+	return err
+}
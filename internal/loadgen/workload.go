@@ -0,0 +1,188 @@
+// Package loadgen simulates swipe traffic against the ExploreService and
+// measures how it copes
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Op is a kind of call the load generator makes
+type Op int
+
+const (
+	OpPutDecision Op = iota
+	OpListLikedYou
+	OpListNewLikedYou
+	OpCountLikedYou
+)
+
+// Ops lists every op, in report order
+var Ops = []Op{OpPutDecision, OpListLikedYou, OpListNewLikedYou, OpCountLikedYou}
+
+var opNames = map[Op]string{
+	OpPutDecision:     "put",
+	OpListLikedYou:    "list",
+	OpListNewLikedYou: "new",
+	OpCountLikedYou:   "count",
+}
+
+// String returns the short name used in mixes
+func (o Op) String() string {
+	return opNames[o]
+}
+
+// RPC returns the name of the RPC the op calls
+func (o Op) RPC() string {
+	switch o {
+	case OpPutDecision:
+		return "PutDecision"
+	case OpListLikedYou:
+		return "ListLikedYou"
+	case OpListNewLikedYou:
+		return "ListNewLikedYou"
+	default:
+		return "CountLikedYou"
+	}
+}
+
+// Mix weights how often each op is picked
+type Mix map[Op]int
+
+// DefaultMix is write heavy, as swiping is far more common than checking likes
+var DefaultMix = Mix{OpPutDecision: 70, OpListLikedYou: 10, OpListNewLikedYou: 10, OpCountLikedYou: 10}
+
+// ParseMix parses comma separated op=weight pairs, such as "put=70,list=10,new=10,count=10".
+// Ops that aren't listed get no traffic.
+func ParseMix(s string) (Mix, error) {
+	byName := make(map[string]Op, len(opNames))
+	for op, name := range opNames {
+		byName[name] = op
+	}
+
+	mix := Mix{}
+	total := 0
+	for _, part := range strings.Split(s, ",") {
+		name, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected op=weight", part)
+		}
+		op, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown op %q, expected put, list, new or count", name)
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight %q for %s", weight, name)
+		}
+		mix[op] = w
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("mix %q has no traffic", s)
+	}
+	return mix, nil
+}
+
+// String formats the mix as ParseMix accepts it
+func (m Mix) String() string {
+	parts := make([]string, 0, len(m))
+	for _, op := range Ops {
+		if w, ok := m[op]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%d", op, w))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Call is one simulated request
+type Call struct {
+	Op        Op
+	Actor     string // Set for PutDecision
+	Recipient string
+	Liked     bool // Set for PutDecision
+}
+
+// Workload generates calls from a population of users whose popularity
+// follows a Zipf distribution: a few users receive most of the likes, and
+// check their likes most often. Actors are picked uniformly.
+// A Workload isn't safe for concurrent use.
+type Workload struct {
+	users      []string
+	rng        *rand.Rand
+	popularity *rand.Zipf
+	likeRatio  float64
+	ops        []Op
+	cumulative []int
+}
+
+// NewWorkload creates a workload over users simulated users. skew is the Zipf
+// exponent and must be greater than 1; higher values concentrate popularity.
+// likeRatio is the fraction of decisions that are likes.
+func NewWorkload(users int, skew, likeRatio float64, mix Mix, seed int64) (*Workload, error) {
+	if users < 2 {
+		return nil, fmt.Errorf("need at least 2 users, got %d", users)
+	}
+	if skew <= 1 {
+		return nil, fmt.Errorf("zipf skew must be greater than 1, got %v", skew)
+	}
+	if likeRatio < 0 || likeRatio > 1 {
+		return nil, fmt.Errorf("like ratio must be between 0 and 1, got %v", likeRatio)
+	}
+
+	w := &Workload{
+		users:     make([]string, users),
+		rng:       rand.New(rand.NewSource(seed)),
+		likeRatio: likeRatio,
+	}
+	for i := range w.users {
+		w.users[i] = "loadgen-user-" + strconv.Itoa(i)
+	}
+	w.popularity = rand.NewZipf(w.rng, skew, 1, uint64(users-1))
+
+	ops := make([]Op, 0, len(mix))
+	for op := range mix {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i] < ops[j] })
+	total := 0
+	for _, op := range ops {
+		if mix[op] == 0 {
+			continue
+		}
+		total += mix[op]
+		w.ops = append(w.ops, op)
+		w.cumulative = append(w.cumulative, total)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("mix has no traffic")
+	}
+
+	return w, nil
+}
+
+// User returns the ID of the i-th simulated user, 0 being the most popular
+func (w *Workload) User(i int) string {
+	return w.users[i]
+}
+
+// Next returns the next call to make
+func (w *Workload) Next() Call {
+	pick := w.rng.Intn(w.cumulative[len(w.cumulative)-1])
+	op := w.ops[sort.SearchInts(w.cumulative, pick+1)]
+
+	call := Call{Op: op, Recipient: w.users[w.popularity.Uint64()]}
+	if op == OpPutDecision {
+		for {
+			call.Actor = w.users[w.rng.Intn(len(w.users))]
+			if call.Actor != call.Recipient {
+				break
+			}
+		}
+		call.Liked = w.rng.Float64() < w.likeRatio
+	}
+	return call
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"muzz-explore-service/internal/config"
+)
+
+// Supported values for Config.LogUserIDs
+const (
+	UserIDsPlain  = "plain"
+	UserIDsHash   = "hash"
+	UserIDsRedact = "redact"
+)
+
+// New creates a logger writing to w at the configured level. Output is JSON
+// when Config.LogFormat is "json", and human readable text otherwise.
+func New(w io.Writer, cfg *config.Config) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch cfg.LogFormat {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.LogFormat)
+	}
+
+	return slog.New(handler).With("service", cfg.ServiceName), nil
+}
+
+// ParseLevel converts a level name such as "debug" or "WARN" into a slog.Level
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", name)
+	}
+	return level, nil
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a context carrying the given request-scoped logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or fallback if there is none
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// UserIDRedactor controls how user IDs appear in logs
+type UserIDRedactor struct {
+	mode    string
+	hashKey []byte
+}
+
+// NewUserIDRedactor creates a redactor for one of the UserIDs* modes. Hashing
+// needs a secret key, so hashes of guessable IDs can't be reversed with a
+// dictionary.
+func NewUserIDRedactor(mode, hashKey string) (UserIDRedactor, error) {
+	switch mode {
+	case UserIDsHash:
+		if hashKey == "" {
+			return UserIDRedactor{}, errors.New("hashing user IDs requires a key")
+		}
+		return UserIDRedactor{mode: mode, hashKey: []byte(hashKey)}, nil
+	case UserIDsPlain, UserIDsRedact:
+		return UserIDRedactor{mode: mode}, nil
+	default:
+		return UserIDRedactor{}, fmt.Errorf("unknown user ID log mode %q", mode)
+	}
+}
+
+// Attr returns a log attribute for the user ID, hashed or redacted as configured.
+// Hashes are stable for a key, so the same user can still be followed across
+// log lines.
+func (r UserIDRedactor) Attr(key, userID string) slog.Attr {
+	switch r.mode {
+	case UserIDsPlain:
+		return slog.String(key, userID)
+	case UserIDsHash:
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(userID))
+		return slog.String(key, hex.EncodeToString(mac.Sum(nil)[:8]))
+	default:
+		return slog.String(key, "[redacted]")
+	}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"muzz-explore-service/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, &config.Config{
+		ServiceName: "explore-service",
+		LogLevel:    "warn",
+		LogFormat:   "json",
+	})
+	require.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "count", 3)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "kept", line["msg"])
+	assert.Equal(t, "explore-service", line["service"])
+	assert.Equal(t, float64(3), line["count"])
+
+	_, err = New(&buf, &config.Config{LogLevel: "loud", LogFormat: "json"})
+	assert.Error(t, err)
+
+	_, err = New(&buf, &config.Config{LogLevel: "info", LogFormat: "xml"})
+	assert.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	fallback := slog.Default()
+	assert.Same(t, fallback, FromContext(context.Background(), fallback))
+
+	scoped := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	ctx := WithLogger(context.Background(), scoped)
+	assert.Same(t, scoped, FromContext(ctx, fallback))
+}
+
+func TestUserIDRedactor(t *testing.T) {
+	plain, err := NewUserIDRedactor(UserIDsPlain, "")
+	require.NoError(t, err)
+	assert.Equal(t, "user1", plain.Attr("user", "user1").Value.String())
+
+	redact, err := NewUserIDRedactor(UserIDsRedact, "")
+	require.NoError(t, err)
+	assert.Equal(t, "[redacted]", redact.Attr("user", "user1").Value.String())
+
+	hash, err := NewUserIDRedactor(UserIDsHash, "key1")
+	require.NoError(t, err)
+	hashed := hash.Attr("user", "user1").Value.String()
+	assert.NotEqual(t, "user1", hashed)
+	assert.Len(t, hashed, 16)
+	assert.Equal(t, hashed, hash.Attr("user", "user1").Value.String(), "hashes must be stable")
+	assert.NotEqual(t, hashed, hash.Attr("user", "user2").Value.String())
+
+	// Hashes depend on the key, so they can't be reversed without it
+	otherKey, err := NewUserIDRedactor(UserIDsHash, "key2")
+	require.NoError(t, err)
+	assert.NotEqual(t, hashed, otherKey.Attr("user", "user1").Value.String())
+
+	_, err = NewUserIDRedactor(UserIDsHash, "")
+	assert.Error(t, err)
+
+	_, err = NewUserIDRedactor("shout", "")
+	assert.Error(t, err)
+}
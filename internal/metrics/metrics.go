@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+const namespace = "explore"
+
+// Metrics holds the Prometheus collectors exported by the service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests  *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
+	decisions *prometheus.CounterVec
+	matches   prometheus.Counter
+}
+
+// New creates the service collectors on a dedicated registry,
+// along with the standard Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Total number of gRPC requests by method and status code.",
+		}, []string{"grpc_service", "grpc_method", "grpc_code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Latency of gRPC requests by method.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"grpc_service", "grpc_method"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_in_flight",
+			Help:      "Number of gRPC requests currently being handled.",
+		}, []string{"grpc_service", "grpc_method"}),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "decisions_total",
+			Help:      "Total number of decisions recorded by type.",
+		}, []string{"decision"}),
+		matches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "matches_total",
+			Help:      "Total number of likes that resulted in a mutual match.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.latency,
+		m.inFlight,
+		m.decisions,
+		m.matches,
+	)
+
+	return m
+}
+
+// Register adds extra collectors, such as the database pool stats, to the registry
+func (m *Metrics) Register(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Handler returns an HTTP handler serving the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// StartRPC marks the start of an RPC and returns a function to be called with
+// the resulting status code once the RPC completes
+func (m *Metrics) StartRPC(service, method string) func(codes.Code) {
+	start := time.Now()
+
+	inFlight := m.inFlight.WithLabelValues(service, method)
+	inFlight.Inc()
+
+	return func(code codes.Code) {
+		inFlight.Dec()
+		m.latency.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(service, method, code.String()).Inc()
+	}
+}
+
+// ObserveDecision records a like or pass, and whether it created a match.
+// previous is the decision it replaced, nil for a first decision, so repeats
+// aren't counted again and re-liking a match doesn't count a new one.
+func (m *Metrics) ObserveDecision(liked bool, previous *bool, mutual bool) {
+	if previous != nil && *previous == liked {
+		return
+	}
+	if !liked {
+		m.decisions.WithLabelValues("pass").Inc()
+		return
+	}
+
+	m.decisions.WithLabelValues("like").Inc()
+	if mutual {
+		m.matches.Inc()
+	}
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestStartRPC(t *testing.T) {
+	m := New()
+
+	done := m.StartRPC("explore.ExploreService", "PutDecision")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.inFlight.WithLabelValues("explore.ExploreService", "PutDecision")))
+
+	done(codes.InvalidArgument)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("explore.ExploreService", "PutDecision")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("explore.ExploreService", "PutDecision", "InvalidArgument")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.latency))
+}
+
+func TestObserveDecision(t *testing.T) {
+	m := New()
+	liked, passed := true, false
+
+	m.ObserveDecision(true, nil, false)
+	m.ObserveDecision(true, nil, true)
+	m.ObserveDecision(false, nil, false)
+
+	// Repeats aren't counted, so re-liking a match doesn't count another one
+	m.ObserveDecision(true, &liked, true)
+	m.ObserveDecision(false, &passed, false)
+
+	// Flips count as the new decision
+	m.ObserveDecision(true, &passed, true)
+	m.ObserveDecision(false, &liked, false)
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.decisions.WithLabelValues("like")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.decisions.WithLabelValues("pass")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.matches))
+}
@@ -0,0 +1,113 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/entitlements"
+)
+
+// Status is a user's like quota at a point in time
+type Status struct {
+	Unlimited bool      // Premium users have no quota
+	Limit     int       // Likes allowed per window
+	Remaining int       // Likes left in the current window
+	ResetAt   time.Time // When the oldest counted like leaves the window, zero if none are counted
+}
+
+// Exhausted reports whether the user may not like anyone else right now
+func (s Status) Exhausted() bool {
+	return !s.Unlimited && s.Remaining <= 0
+}
+
+// Manager enforces a rolling window like quota for free tier users
+type Manager struct {
+	queries      db.Querier
+	entitlements entitlements.Entitlements
+	limit        int
+	window       time.Duration
+	now          func() time.Time
+}
+
+// NewManager creates a quota allowing free users limit likes per window
+func NewManager(queries db.Querier, ents entitlements.Entitlements, limit int, window time.Duration) *Manager {
+	return &Manager{
+		queries:      queries,
+		entitlements: ents,
+		limit:        limit,
+		window:       window,
+		now:          time.Now,
+	}
+}
+
+// Status returns the user's current quota
+func (m *Manager) Status(ctx context.Context, userID string) (Status, error) {
+	return m.status(ctx, m.queries, userID)
+}
+
+func (m *Manager) status(ctx context.Context, queries db.Querier, userID string) (Status, error) {
+	tier, err := m.entitlements.Tier(ctx, userID)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to look up tier: %w", err)
+	}
+	if tier == entitlements.TierPremium {
+		return Status{Unlimited: true}, nil
+	}
+
+	usage, err := queries.GetQuotaUsage(ctx, db.GetQuotaUsageParams{
+		ActorUserID: userID,
+		WindowStart: m.now().Add(-m.window),
+	})
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to read quota usage: %w", err)
+	}
+
+	status := Status{
+		Limit:     m.limit,
+		Remaining: max(m.limit-int(usage.Used), 0),
+	}
+	if usage.Used > 0 {
+		status.ResetAt = usage.Oldest.Add(m.window)
+	}
+	return status, nil
+}
+
+// Claim counts a like of the recipient against the actor's quota, returning
+// false with the actor's quota if it's used up. Liking someone already counted
+// in the window is free. queries must be bound to the transaction recording the
+// like, holding the actor's decision lock, so concurrent likes can't both take
+// the last one.
+func (m *Manager) Claim(ctx context.Context, queries db.Querier, actorUserID, recipientUserID string) (Status, bool, error) {
+	status, err := m.status(ctx, queries, actorUserID)
+	if err != nil || status.Unlimited {
+		return status, err == nil, err
+	}
+
+	windowStart := m.now().Add(-m.window)
+	counted, err := queries.IsQuotaLikeCounted(ctx, db.IsQuotaLikeCountedParams{
+		ActorUserID:     actorUserID,
+		RecipientUserID: recipientUserID,
+		WindowStart:     windowStart,
+	})
+	if err != nil {
+		return Status{}, false, fmt.Errorf("failed to read quota usage: %w", err)
+	}
+	if counted {
+		return status, true, nil
+	}
+	if status.Exhausted() {
+		return status, false, nil
+	}
+
+	err = queries.RecordQuotaLike(ctx, db.RecordQuotaLikeParams{
+		ActorUserID:     actorUserID,
+		RecipientUserID: recipientUserID,
+		WindowStart:     windowStart,
+	})
+	if err != nil {
+		return Status{}, false, fmt.Errorf("failed to record quota usage: %w", err)
+	}
+	return status, true, nil
+}
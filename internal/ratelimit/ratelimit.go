@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second, holding at most Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Store keeps token buckets keyed by caller and RPC. The in-memory store
+// suits a single replica; a shared store lets replicas enforce one budget.
+type Store interface {
+	// Take removes a token from the bucket for key, creating it full if needed.
+	// When the bucket is empty it returns false and how long until a token is available.
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// Limiter applies per-RPC limits to callers
+type Limiter struct {
+	store        Store
+	limits       map[string]Limit
+	defaultLimit *Limit
+}
+
+// NewLimiter creates a limiter applying limits by RPC method name, falling back
+// to defaultLimit for other methods. A nil defaultLimit leaves them unlimited.
+func NewLimiter(store Store, limits map[string]Limit, defaultLimit *Limit) *Limiter {
+	return &Limiter{
+		store:        store,
+		limits:       limits,
+		defaultLimit: defaultLimit,
+	}
+}
+
+// Allow takes a token for the caller on the given method. When the caller is
+// over its limit it returns false and the time to wait before retrying.
+func (l *Limiter) Allow(ctx context.Context, method, caller string) (bool, time.Duration, error) {
+	limit, ok := l.limits[method]
+	if !ok {
+		if l.defaultLimit == nil {
+			return true, 0, nil
+		}
+		limit = *l.defaultLimit
+	}
+
+	return l.store.Take(ctx, method+"|"+caller, limit)
+}
+
+// ParseLimit parses a "rate:burst" spec, where rate is tokens per second
+func ParseLimit(spec string) (Limit, error) {
+	rateStr, burstStr, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, want rate:burst", spec)
+	}
+
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+		return Limit{}, fmt.Errorf("invalid rate in rate limit %q", spec)
+	}
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 1 {
+		return Limit{}, fmt.Errorf("invalid burst in rate limit %q", spec)
+	}
+
+	return Limit{Rate: rate, Burst: burst}, nil
+}
+
+// ParseLimits parses a comma separated list of "Method=rate:burst" specs
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, limitSpec, ok := strings.Cut(entry, "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid rate limit entry %q, want Method=rate:burst", entry)
+		}
+		limit, err := ParseLimit(limitSpec)
+		if err != nil {
+			return nil, err
+		}
+		limits[strings.TrimSpace(method)] = limit
+	}
+	return limits, nil
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	// A new bucket starts full
+	for i := 0; i < 3; i++ {
+		allowed, _, err := store.Take(ctx, "user1", limit)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter, err := store.Take(ctx, "user1", limit)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Other callers have their own bucket
+	allowed, _, err = store.Take(ctx, "user2", limit)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Tokens refill over time
+	now = now.Add(500 * time.Millisecond)
+	allowed, _, err = store.Take(ctx, "user1", limit)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Full buckets are swept
+	now = now.Add(time.Hour)
+	store.sweep(now)
+	assert.Empty(t, store.buckets)
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore(), map[string]Limit{
+		"PutDecision": {Rate: 1, Burst: 1},
+	}, nil)
+	ctx := context.Background()
+
+	allowed, _, err := limiter.Allow(ctx, "PutDecision", "user1")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "PutDecision", "user1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Positive(t, retryAfter)
+
+	// Methods without a limit are unrestricted when there is no default
+	for i := 0; i < 10; i++ {
+		allowed, _, err = limiter.Allow(ctx, "CountLikedYou", "user1")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("PutDecision=2:30, ListLikedYou=0.5:5")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"PutDecision":  {Rate: 2, Burst: 30},
+		"ListLikedYou": {Rate: 0.5, Burst: 5},
+	}, limits)
+
+	limits, err = ParseLimits("")
+	require.NoError(t, err)
+	assert.Empty(t, limits)
+
+	for _, spec := range []string{"PutDecision", "PutDecision=2", "PutDecision=x:1", "PutDecision=1:0", "=1:1", "PutDecision=-1:5"} {
+		_, err := ParseLimits(spec)
+		assert.Error(t, err, spec)
+	}
+}
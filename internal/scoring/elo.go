@@ -0,0 +1,25 @@
+package scoring
+
+import "math"
+
+const (
+	// InitialScore is the desirability of a user who hasn't received any decisions
+	InitialScore = 1000.0
+
+	// k caps how far a single decision can move a score
+	k = 32.0
+)
+
+// Delta returns how much a decision changes the recipient's score. Each
+// decision is scored as an Elo match between recipient and actor, which the
+// recipient wins with a like and loses with a pass. Likes from users with a
+// higher score than the recipient therefore count for more, and passes from
+// them cost less, than those from users with a lower score.
+func Delta(recipientScore, actorScore float64, liked bool) float64 {
+	expected := 1 / (1 + math.Pow(10, (actorScore-recipientScore)/400))
+	outcome := 0.0
+	if liked {
+		outcome = 1
+	}
+	return k * (outcome - expected)
+}
@@ -0,0 +1,175 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"muzz-explore-service/internal/db"
+)
+
+const recomputeBatchSize = 1000
+
+// Score is a user's desirability and the decisions it was computed from
+type Score struct {
+	UserID         string
+	Score          float64
+	LikesReceived  int64
+	PassesReceived int64
+	UpdatedAt      time.Time // Zero for users who haven't received any decisions
+}
+
+// Scorer maintains desirability scores in the user_scores table
+type Scorer struct {
+	queries db.Querier
+}
+
+// NewScorer creates a scorer backed by the given queries
+func NewScorer(queries db.Querier) *Scorer {
+	return &Scorer{queries: queries}
+}
+
+// Get returns the user's score, or the initial score if they haven't received any decisions
+func (s *Scorer) Get(ctx context.Context, userID string) (Score, error) {
+	return s.get(ctx, s.queries, userID)
+}
+
+func (s *Scorer) get(ctx context.Context, queries db.Querier, userID string) (Score, error) {
+	row, err := queries.GetUserScore(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Score{UserID: userID, Score: InitialScore}, nil
+	}
+	if err != nil {
+		return Score{}, fmt.Errorf("failed to get user score: %w", err)
+	}
+	return Score{
+		UserID:         row.UserID,
+		Score:          row.Score,
+		LikesReceived:  row.LikesReceived,
+		PassesReceived: row.PassesReceived,
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
+
+// Change is a decision, and the decision it replaces if the actor had already decided
+type Change struct {
+	ActorUserID     string
+	RecipientUserID string
+	Liked           bool
+	Previous        *bool // Whether the actor liked the recipient before, nil for a first decision
+}
+
+// Apply updates the recipient's score and counters with a changed decision,
+// undoing the decision it replaces first. Decisions that didn't change are
+// ignored. Counters always match the decisions, while the score can differ
+// from Recompute's by the order decisions are replayed in. queries must be
+// bound to the transaction recording the decision, which holds the lock on
+// the recipient's score until it commits.
+func (s *Scorer) Apply(ctx context.Context, queries db.Querier, change Change) error {
+	if change.Previous != nil && *change.Previous == change.Liked {
+		return nil
+	}
+
+	recipient, err := queries.LockUserScore(ctx, db.LockUserScoreParams{
+		UserID:       change.RecipientUserID,
+		InitialScore: InitialScore,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lock user score: %w", err)
+	}
+	actor, err := s.get(ctx, queries, change.ActorUserID)
+	if err != nil {
+		return err
+	}
+
+	score := recipient.Score
+	if change.Previous != nil {
+		score -= Delta(score, actor.Score, *change.Previous)
+		if *change.Previous {
+			recipient.LikesReceived--
+		} else {
+			recipient.PassesReceived--
+		}
+	}
+	score += Delta(score, actor.Score, change.Liked)
+	if change.Liked {
+		recipient.LikesReceived++
+	} else {
+		recipient.PassesReceived++
+	}
+
+	err = queries.SetUserScore(ctx, db.SetUserScoreParams{
+		UserID:         change.RecipientUserID,
+		Score:          score,
+		LikesReceived:  recipient.LikesReceived,
+		PassesReceived: recipient.PassesReceived,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update user score: %w", err)
+	}
+	return nil
+}
+
+// Recompute replays every decision in the order it was last made, starting all
+// users from the initial score, and overwrites the stored scores. Returns the
+// number of users scored.
+func (s *Scorer) Recompute(ctx context.Context) (int, error) {
+	scores := make(map[string]*Score)
+	score := func(userID string) *Score {
+		sc, ok := scores[userID]
+		if !ok {
+			sc = &Score{UserID: userID, Score: InitialScore}
+			scores[userID] = sc
+		}
+		return sc
+	}
+
+	var cursor db.ListDecisionsAfterParams
+	for {
+		cursor.PageLimit = recomputeBatchSize
+		decisions, err := s.queries.ListDecisionsAfter(ctx, cursor)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list decisions: %w", err)
+		}
+
+		for _, d := range decisions {
+			recipient := score(d.RecipientUserID)
+			recipient.Score += Delta(recipient.Score, score(d.ActorUserID).Score, d.Liked)
+			if d.Liked {
+				recipient.LikesReceived++
+			} else {
+				recipient.PassesReceived++
+			}
+		}
+
+		if len(decisions) < recomputeBatchSize {
+			break
+		}
+		last := decisions[len(decisions)-1]
+		cursor.UpdatedAtCursor = last.UpdatedAt
+		cursor.ActorUserIDCursor = last.ActorUserID
+		cursor.RecipientUserIDCursor = last.RecipientUserID
+	}
+
+	written := 0
+	for _, sc := range scores {
+		// Users who only made decisions keep no row, like with incremental updates
+		if sc.LikesReceived == 0 && sc.PassesReceived == 0 {
+			continue
+		}
+		err := s.queries.SetUserScore(ctx, db.SetUserScoreParams{
+			UserID:         sc.UserID,
+			Score:          sc.Score,
+			LikesReceived:  sc.LikesReceived,
+			PassesReceived: sc.PassesReceived,
+		})
+		if err != nil {
+			return written, fmt.Errorf("failed to store user score: %w", err)
+		}
+		written++
+	}
+	return written, nil
+}
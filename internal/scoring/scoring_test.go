@@ -0,0 +1,80 @@
+package scoring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"muzz-explore-service/internal/db"
+)
+
+func TestDelta(t *testing.T) {
+	// Evenly matched users move by half of k
+	assert.InDelta(t, 16, Delta(1000, 1000, true), 1e-9)
+	assert.InDelta(t, -16, Delta(1000, 1000, false), 1e-9)
+
+	// Likes from more desirable users count for more, passes from them cost less
+	assert.Greater(t, Delta(1000, 1400, true), Delta(1000, 600, true))
+	assert.Greater(t, Delta(1000, 1400, false), Delta(1000, 600, false))
+}
+
+// fakeQueries serves decisions in pages and records the scores written
+type fakeQueries struct {
+	db.Querier
+	decisions []db.ListDecisionsAfterRow
+	stored    map[string]db.SetUserScoreParams
+}
+
+func (f *fakeQueries) ListDecisionsAfter(_ context.Context, arg db.ListDecisionsAfterParams) ([]db.ListDecisionsAfterRow, error) {
+	var page []db.ListDecisionsAfterRow
+	for _, d := range f.decisions {
+		if !arg.UpdatedAtCursor.IsZero() && !d.UpdatedAt.After(arg.UpdatedAtCursor) {
+			continue
+		}
+		if len(page) == int(arg.PageLimit) {
+			break
+		}
+		page = append(page, d)
+	}
+	return page, nil
+}
+
+func (f *fakeQueries) SetUserScore(_ context.Context, arg db.SetUserScoreParams) error {
+	f.stored[arg.UserID] = arg
+	return nil
+}
+
+func TestRecompute(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	queries := &fakeQueries{stored: make(map[string]db.SetUserScoreParams)}
+	for i := 0; i < recomputeBatchSize+1; i++ {
+		queries.decisions = append(queries.decisions, db.ListDecisionsAfterRow{
+			ActorUserID:     "user1",
+			RecipientUserID: "user2",
+			Liked:           i%2 == 0,
+			UpdatedAt:       start.Add(time.Duration(i) * time.Second),
+		})
+	}
+	queries.decisions = append(queries.decisions, db.ListDecisionsAfterRow{
+		ActorUserID:     "user2",
+		RecipientUserID: "user3",
+		Liked:           false,
+		UpdatedAt:       start.Add(time.Hour),
+	})
+
+	written, err := NewScorer(queries).Recompute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, written)
+
+	// Every page was replayed
+	assert.Equal(t, int64(recomputeBatchSize/2+1), queries.stored["user2"].LikesReceived)
+	assert.Equal(t, int64(recomputeBatchSize/2), queries.stored["user2"].PassesReceived)
+	assert.Equal(t, int64(1), queries.stored["user3"].PassesReceived)
+	assert.Less(t, queries.stored["user3"].Score, InitialScore)
+
+	// Users who only made decisions aren't stored
+	assert.NotContains(t, queries.stored, "user1")
+}
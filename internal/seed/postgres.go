@@ -0,0 +1,40 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// resetQueries delete rows about generated users from every table, with $1
+// matching their IDs
+var resetQueries = []string{
+	`DELETE FROM decisions WHERE actor_user_id LIKE $1 OR recipient_user_id LIKE $1`,
+	`DELETE FROM expired_likes WHERE actor_user_id LIKE $1 OR recipient_user_id LIKE $1`,
+	`DELETE FROM quota_likes WHERE actor_user_id LIKE $1 OR recipient_user_id LIKE $1`,
+	`DELETE FROM likes_seen WHERE recipient_user_id LIKE $1`,
+	`DELETE FROM user_scores WHERE user_id LIKE $1`,
+}
+
+// Reset deletes everything stored about generated users, leaving other users'
+// data alone, and returns how many decisions were deleted
+func Reset(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var decisions int64
+	for i, query := range resetQueries {
+		tag, err := tx.Exec(ctx, query, UserPrefix+"%")
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete seeded rows: %w", err)
+		}
+		if i == 0 {
+			decisions = tag.RowsAffected()
+		}
+	}
+	return decisions, tx.Commit(ctx)
+}
@@ -0,0 +1,280 @@
+// Package seed generates a reproducible graph of synthetic users and decisions
+// for local development and tests
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"muzz-explore-service/internal/importer"
+)
+
+// UserPrefix starts every generated user ID, so seeded data can be told apart from real users
+const UserPrefix = "seed-user-"
+
+// User returns the ID of the i-th generated user. User 0 is the popular user.
+func User(i int) string {
+	return fmt.Sprintf("%s%d", UserPrefix, i)
+}
+
+// Config shapes the generated graph
+type Config struct {
+	Users          int           // Population size
+	Decisions      int           // Decisions each user makes about random other users
+	PopularLikers  int           // Users who also like user 0, so its likers span several pages
+	LikeRatio      float64       // Fraction of decisions that are likes
+	SuperLikeRatio float64       // Fraction of likes that are super-likes
+	MutualRatio    float64       // Fraction of likes the recipient likes back, if they haven't decided yet
+	FlipRatio      float64       // Fraction of decisions later changed from like to pass or back
+	CollisionRatio float64       // Fraction of likes made at exactly the same time as the recipient's previous like
+	Start          time.Time     // When the first decisions can be made
+	Span           time.Duration // How long after Start decisions keep being made
+	Seed           int64
+}
+
+// DefaultConfig returns a small graph suitable for a laptop
+func DefaultConfig() Config {
+	return Config{
+		Users:          1000,
+		Decisions:      20,
+		PopularLikers:  120,
+		LikeRatio:      0.5,
+		SuperLikeRatio: 0.05,
+		MutualRatio:    0.3,
+		FlipRatio:      0.05,
+		CollisionRatio: 0.05,
+		Start:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Span:           30 * 24 * time.Hour,
+		Seed:           1,
+	}
+}
+
+// Validate checks the config describes a graph that can be generated
+func (c Config) Validate() error {
+	ratios := []struct {
+		name  string
+		value float64
+	}{
+		{"like ratio", c.LikeRatio},
+		{"super-like ratio", c.SuperLikeRatio},
+		{"mutual ratio", c.MutualRatio},
+		{"flip ratio", c.FlipRatio},
+		{"collision ratio", c.CollisionRatio},
+	}
+	for _, ratio := range ratios {
+		if ratio.value < 0 || ratio.value > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", ratio.name, ratio.value)
+		}
+	}
+
+	switch {
+	case c.Users < 2:
+		return fmt.Errorf("users must be at least 2, got %d", c.Users)
+	case c.Decisions < 0 || c.Decisions >= c.Users:
+		return fmt.Errorf("decisions per user must be between 0 and %d, got %d", c.Users-1, c.Decisions)
+	case c.PopularLikers < 0 || c.PopularLikers >= c.Users:
+		return fmt.Errorf("popular likers must be between 0 and %d, got %d", c.Users-1, c.PopularLikers)
+	case c.Start.IsZero():
+		return errors.New("start is required")
+	case c.Span <= 0:
+		return fmt.Errorf("span must be positive, got %s", c.Span)
+	}
+	return nil
+}
+
+// Stats counts what was generated
+type Stats struct {
+	Records    int // Decisions plus flips
+	Decisions  int // Pairs decided
+	Likes      int // Decisions that end as likes
+	SuperLikes int // Likes that end as super-likes
+	Mutual     int // Pairs that end up liking each other, counted once
+	Flips      int // Decisions changed after they were first made
+	Collisions int // Likes made at the same time as another like to the same recipient
+}
+
+// Dataset is a generated graph, as records in the import format
+type Dataset struct {
+	// Records in the order they were made, flips after the decisions they change
+	Records []importer.Record
+	Stats   Stats
+
+	final map[[2]string]importer.Record
+}
+
+// generator holds the state of one Generate call
+type generator struct {
+	cfg     Config
+	rng     *rand.Rand
+	end     time.Time
+	records []importer.Record
+	decided map[[2]string]bool   // By actor and recipient
+	lastAt  map[string]time.Time // Of the latest like each recipient received
+	stats   Stats
+}
+
+// Generate builds the graph described by cfg. The same config always
+// generates the same records.
+func Generate(cfg Config) (*Dataset, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		cfg:     cfg,
+		rng:     rand.New(rand.NewSource(cfg.Seed)),
+		end:     cfg.Start.Add(cfg.Span),
+		decided: make(map[[2]string]bool),
+		lastAt:  make(map[string]time.Time),
+	}
+
+	for i := 1; i <= cfg.PopularLikers; i++ {
+		g.decide(User(i), User(0), true, g.between(cfg.Start))
+	}
+	for i := range cfg.Users {
+		// Retries are bounded, as users who were liked back already decided on some
+		// recipients and a dense graph may not have enough undecided ones left
+		for made, attempts := 0, 0; made < cfg.Decisions && attempts < 10*cfg.Decisions; attempts++ {
+			j := g.rng.Intn(cfg.Users)
+			if j == i {
+				continue
+			}
+			if g.decided[[2]string{User(i), User(j)}] {
+				continue
+			}
+			g.decide(User(i), User(j), g.rng.Float64() < cfg.LikeRatio, g.between(cfg.Start))
+			made++
+		}
+	}
+	g.flip()
+
+	// Keep the order decisions were made in, so imported IDs roughly follow time
+	sort.SliceStable(g.records, func(a, b int) bool {
+		return g.records[a].UpdatedAt.Before(g.records[b].UpdatedAt)
+	})
+
+	dataset := &Dataset{Records: g.records, final: make(map[[2]string]importer.Record)}
+	for _, record := range g.records {
+		key := [2]string{record.ActorUserID, record.RecipientUserID}
+		if current, ok := dataset.final[key]; !ok || record.UpdatedAt.After(current.UpdatedAt) {
+			dataset.final[key] = record
+		}
+	}
+	dataset.Stats = g.stats
+	dataset.Stats.Records = len(g.records)
+	dataset.Stats.Decisions = len(dataset.final)
+	for key, record := range dataset.final {
+		if !record.Liked {
+			continue
+		}
+		dataset.Stats.Likes++
+		if record.SuperLike {
+			dataset.Stats.SuperLikes++
+		}
+		if reverse, ok := dataset.final[[2]string{key[1], key[0]}]; ok && reverse.Liked && key[0] < key[1] {
+			dataset.Stats.Mutual++
+		}
+	}
+
+	return dataset, nil
+}
+
+// between returns a random time in [from, end), at the microsecond precision Postgres stores
+func (g *generator) between(from time.Time) time.Time {
+	window := g.end.Sub(from).Microseconds()
+	if window <= 0 {
+		return from
+	}
+	return from.Add(time.Duration(g.rng.Int63n(window)) * time.Microsecond).Truncate(time.Microsecond)
+}
+
+// decide records the actor's first decision about the recipient, and maybe a like back
+func (g *generator) decide(actor, recipient string, liked bool, at time.Time) {
+	record := importer.Record{ActorUserID: actor, RecipientUserID: recipient, Liked: liked}
+	if liked {
+		record.SuperLike = g.rng.Float64() < g.cfg.SuperLikeRatio
+		if last, ok := g.lastAt[recipient]; ok && g.rng.Float64() < g.cfg.CollisionRatio {
+			at = last
+			g.stats.Collisions++
+		}
+		g.lastAt[recipient] = at
+	}
+	record.CreatedAt, record.UpdatedAt = at, at
+
+	g.decided[[2]string{actor, recipient}] = true
+	g.records = append(g.records, record)
+
+	if !liked || g.rng.Float64() >= g.cfg.MutualRatio {
+		return
+	}
+	if !g.decided[[2]string{recipient, actor}] {
+		g.decide(recipient, actor, true, g.between(at))
+	}
+}
+
+// flip changes some decisions after they were made, as a second record for the pair
+func (g *generator) flip() {
+	decisions := len(g.records)
+	for i := range decisions {
+		if g.rng.Float64() >= g.cfg.FlipRatio {
+			continue
+		}
+		original := g.records[i]
+		g.records = append(g.records, importer.Record{
+			ActorUserID:     original.ActorUserID,
+			RecipientUserID: original.RecipientUserID,
+			Liked:           !original.Liked,
+			CreatedAt:       original.CreatedAt,
+			UpdatedAt:       g.between(original.CreatedAt.Add(time.Microsecond)),
+		})
+		g.stats.Flips++
+	}
+}
+
+// Decision returns the final decision the actor made about the recipient
+func (d *Dataset) Decision(actor, recipient string) (importer.Record, bool) {
+	record, ok := d.final[[2]string{actor, recipient}]
+	return record, ok
+}
+
+// Likers returns who ends up liking the recipient, sorted by user ID. With
+// newOnly, users the recipient likes back are left out, like ListNewLikedYou.
+func (d *Dataset) Likers(recipient string, newOnly bool) []string {
+	var likers []string
+	for key, record := range d.final {
+		if key[1] != recipient || !record.Liked {
+			continue
+		}
+		if reverse, ok := d.final[[2]string{recipient, key[0]}]; newOnly && ok && reverse.Liked {
+			continue
+		}
+		likers = append(likers, key[0])
+	}
+	sort.Strings(likers)
+	return likers
+}
+
+// Load merges the records into a store in batches. Records merge
+// last-write-wins, so loading the same dataset again changes nothing.
+func (d *Dataset) Load(ctx context.Context, store importer.Store, name string, batchSize int) (importer.MergeResult, error) {
+	if batchSize <= 0 {
+		batchSize = importer.DefaultBatchSize
+	}
+
+	var total importer.MergeResult
+	for start := 0; start < len(d.Records); start += batchSize {
+		end := min(start+batchSize, len(d.Records))
+		result, err := store.Merge(ctx, name, d.Records[start:end], int64(end))
+		if err != nil {
+			return total, fmt.Errorf("failed to merge records %d to %d: %w", start+1, end, err)
+		}
+		total.Inserted += result.Inserted
+		total.Updated += result.Updated
+		total.Stale += result.Stale
+	}
+	return total, nil
+}
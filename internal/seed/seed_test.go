@@ -0,0 +1,128 @@
+package seed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"muzz-explore-service/internal/importer"
+)
+
+func TestGenerate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Users = 200
+	cfg.PopularLikers = 60
+	cfg.CollisionRatio = 0.2
+
+	dataset, err := Generate(cfg)
+	require.NoError(t, err)
+
+	stats := dataset.Stats
+	assert.Equal(t, len(dataset.Records), stats.Records)
+	assert.Equal(t, stats.Records-stats.Flips, stats.Decisions, "every decision but flips is a new pair")
+	assert.Greater(t, stats.Flips, 0)
+	assert.Greater(t, stats.Mutual, 0)
+	assert.Greater(t, stats.SuperLikes, 0)
+	assert.Greater(t, stats.Collisions, 0)
+	assert.Less(t, stats.Likes, stats.Decisions)
+
+	for i, record := range dataset.Records {
+		require.NoError(t, record.Validate(), "record %d", i)
+		assert.True(t, record.CreatedAt.Equal(record.CreatedAt.Truncate(1000)), "microsecond precision")
+		if i > 0 {
+			assert.False(t, record.UpdatedAt.Before(dataset.Records[i-1].UpdatedAt), "records are in order")
+		}
+	}
+
+	// The popular user's likers span several pages, and those it likes back aren't new
+	likers := dataset.Likers(User(0), false)
+	newLikers := dataset.Likers(User(0), true)
+	assert.GreaterOrEqual(t, len(likers), 50)
+	assert.Less(t, len(newLikers), len(likers))
+	for _, liker := range likers {
+		record, ok := dataset.Decision(liker, User(0))
+		require.True(t, ok)
+		assert.True(t, record.Liked)
+	}
+	for _, liker := range newLikers {
+		reverse, ok := dataset.Decision(User(0), liker)
+		assert.False(t, ok && reverse.Liked, liker)
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Users = 100
+	cfg.PopularLikers = 10
+
+	a, err := Generate(cfg)
+	require.NoError(t, err)
+	b, err := Generate(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, a.Records, b.Records)
+
+	cfg.Seed = 2
+	c, err := Generate(cfg)
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Records, c.Records)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{"default", func(*Config) {}, ""},
+		{"too few users", func(c *Config) { c.Users = 1 }, "users must be at least 2, got 1"},
+		{"too many decisions", func(c *Config) { c.Decisions = c.Users }, "decisions per user must be between 0 and 999, got 1000"},
+		{"too many popular likers", func(c *Config) { c.PopularLikers = c.Users }, "popular likers must be between 0 and 999, got 1000"},
+		{"ratio out of range", func(c *Config) { c.FlipRatio = 1.5 }, "flip ratio must be between 0 and 1, got 1.5"},
+		{"no span", func(c *Config) { c.Span = 0 }, "span must be positive, got 0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+// fakeStore records merged batches
+type fakeStore struct {
+	importer.Store
+	batches   [][]importer.Record
+	positions []int64
+}
+
+func (f *fakeStore) Merge(ctx context.Context, name string, batch []importer.Record, position int64) (importer.MergeResult, error) {
+	f.batches = append(f.batches, batch)
+	f.positions = append(f.positions, position)
+	return importer.MergeResult{Inserted: int64(len(batch))}, nil
+}
+
+func TestLoad(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Users = 20
+	cfg.Decisions = 5
+	cfg.PopularLikers = 0
+	dataset, err := Generate(cfg)
+	require.NoError(t, err)
+
+	store := &fakeStore{}
+	result, err := dataset.Load(context.Background(), store, "seed", 40)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(dataset.Records)), result.Inserted)
+	require.Len(t, store.batches, (len(dataset.Records)+39)/40)
+	assert.Len(t, store.batches[0], 40)
+	assert.Equal(t, int64(len(dataset.Records)), store.positions[len(store.positions)-1])
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/logging"
+	"muzz-explore-service/internal/metrics"
+	"muzz-explore-service/internal/ratelimit"
+	"muzz-explore-service/internal/tracing"
+)
+
+// requestIDHeader is the metadata key used to accept and echo request IDs
+const requestIDHeader = "x-request-id"
+
+// tracingInterceptor starts a server span for each RPC, continuing any trace
+// propagated by the caller in the request metadata
+func tracingInterceptor(tp trace.TracerProvider) grpc.UnaryServerInterceptor {
+	tracer := tp.Tracer("muzz-explore-service/internal/server")
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		service, method := splitMethodName(info.FullMethod)
+
+		ctx = tracing.ExtractIncoming(ctx)
+		ctx, span := tracer.Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.RPCSystemGRPC,
+				semconv.RPCService(service),
+				semconv.RPCMethod(method),
+			),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if err != nil {
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		return resp, err
+	}
+}
+
+// loggingInterceptor attaches a request-scoped logger to the context and
+// writes an access log line once each RPC completes
+func loggingInterceptor(logger *slog.Logger, redactor logging.UserIDRedactor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		requestID := incomingRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+		attrs := []any{
+			slog.String("rpc_method", info.FullMethod),
+			slog.String("request_id", requestID),
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+		}
+		if r, ok := req.(interface{ GetActorUserId() string }); ok && r.GetActorUserId() != "" {
+			attrs = append(attrs, redactor.Attr("actor_user_id", r.GetActorUserId()))
+		}
+		if r, ok := req.(interface{ GetRecipientUserId() string }); ok && r.GetRecipientUserId() != "" {
+			attrs = append(attrs, redactor.Attr("recipient_user_id", r.GetRecipientUserId()))
+		}
+		if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" {
+			attrs = append(attrs, redactor.Attr("user_id", r.GetUserId()))
+		}
+
+		reqLogger := logger.With(attrs...)
+		ctx = logging.WithLogger(ctx, reqLogger)
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+			level = slog.LevelError
+		}
+		reqLogger.Log(ctx, level, "rpc completed",
+			slog.String("grpc_code", code.String()),
+			slog.Duration("duration", time.Since(start)),
+		)
+
+		return resp, err
+	}
+}
+
+// incomingRequestID returns the caller supplied request ID, generating one if absent
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// metricsInterceptor records request counts, latency and in-flight requests per RPC
+func metricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := m.StartRPC(splitMethodName(info.FullMethod))
+		resp, err := handler(ctx, req)
+		done(status.Code(err))
+		return resp, err
+	}
+}
+
+// unauthenticatedServices are reachable without credentials, so that
+// infrastructure such as Kubernetes probes can call them
+var unauthenticatedServices = map[string]bool{
+	healthpb.Health_ServiceDesc.ServiceName: true,
+}
+
+// authInterceptor authenticates the caller and stores the principal in the context
+func authInterceptor(authenticator auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if service, _ := splitMethodName(info.FullMethod); unauthenticatedServices[service] {
+			return handler(ctx, req)
+		}
+
+		principal, err := authenticator.Authenticate(ctx)
+		if err != nil {
+			if _, ok := status.FromError(err); !ok {
+				err = status.Error(codes.Unauthenticated, "authentication failed")
+			}
+			return nil, err
+		}
+
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// rateLimitInterceptor rejects callers that exceed their per-RPC token bucket
+// with ResourceExhausted, setting a retry-after header in seconds
+func rateLimitInterceptor(limiter *ratelimit.Limiter, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		service, method := splitMethodName(info.FullMethod)
+		if unauthenticatedServices[service] {
+			return handler(ctx, req)
+		}
+
+		allowed, retryAfter, err := limiter.Allow(ctx, method, rateLimitKey(ctx, req))
+		if err != nil {
+			// Fail open so an unavailable limiter store doesn't take the service down
+			logger.WarnContext(ctx, "rate limiter unavailable", "error", err)
+			return handler(ctx, req)
+		}
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+
+			st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+			if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+				st = detailed
+			}
+			return nil, st.Err()
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitKey identifies the caller. Users are limited by their own ID, and
+// internal services by the user they act for, so one noisy user doesn't
+// throttle everyone behind the same backend. Anonymous callers fall back to
+// the user in the request, then the peer address.
+func rateLimitKey(ctx context.Context, req any) string {
+	user := requestUser(req)
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		if !p.HasRole(auth.RoleInternal) {
+			return "user:" + p.Subject
+		}
+		if user == "" {
+			return "principal:" + p.Subject
+		}
+	}
+	if user != "" {
+		return "user:" + user
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "peer:" + host
+	}
+	return "anonymous"
+}
+
+// requestUser returns the user a request is about: its actor, recipient or user
+func requestUser(req any) string {
+	if r, ok := req.(interface{ GetActorUserId() string }); ok && r.GetActorUserId() != "" {
+		return r.GetActorUserId()
+	}
+	if r, ok := req.(interface{ GetRecipientUserId() string }); ok && r.GetRecipientUserId() != "" {
+		return r.GetRecipientUserId()
+	}
+	if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" {
+		return r.GetUserId()
+	}
+	return ""
+}
+
+// splitMethodName splits a gRPC full method name ("/package.Service/Method")
+// into its service and method parts
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if service, method, ok := strings.Cut(fullMethod, "/"); ok {
+		return service, method
+	}
+	return "unknown", fullMethod
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/ratelimit"
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+func okHandler(ctx context.Context, req any) (any, error) {
+	return "ok", nil
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{
+		"PutDecision": {Rate: 1, Burst: 2},
+	}, nil)
+	interceptor := rateLimitInterceptor(limiter, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/explore.ExploreService/PutDecision"}
+
+	decide := func(actor string) error {
+		_, err := interceptor(context.Background(), &pb.PutDecisionRequest{ActorUserId: actor}, info, okHandler)
+		return err
+	}
+
+	require.NoError(t, decide("user1"))
+	require.NoError(t, decide("user1"))
+
+	err := decide("user1")
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Positive(t, retryInfo.RetryDelay.AsDuration())
+
+	// Each actor has their own budget
+	assert.NoError(t, decide("user2"))
+}
+
+func TestRateLimitInterceptor_InternalPrincipal(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{
+		"PutDecision": {Rate: 1, Burst: 1},
+	}, nil)
+	interceptor := rateLimitInterceptor(limiter, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/explore.ExploreService/PutDecision"}
+	backend := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "backend", Roles: []string{auth.RoleInternal}})
+
+	decide := func(ctx context.Context, actor string) codes.Code {
+		_, err := interceptor(ctx, &pb.PutDecisionRequest{ActorUserId: actor}, info, okHandler)
+		return status.Code(err)
+	}
+
+	// A noisy user behind the backend doesn't use up the others' budgets
+	assert.Equal(t, codes.OK, decide(backend, "user1"))
+	assert.Equal(t, codes.ResourceExhausted, decide(backend, "user1"))
+	assert.Equal(t, codes.OK, decide(backend, "user2"))
+
+	// Users share their budget whether they call directly or through the backend
+	user2 := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user2"})
+	assert.Equal(t, codes.ResourceExhausted, decide(user2, "user2"))
+}
+
+func TestRateLimitKey(t *testing.T) {
+	principal := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user9"})
+	internal := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "backend", Roles: []string{auth.RoleInternal}})
+
+	assert.Equal(t, "user:user9", rateLimitKey(principal, &pb.PutDecisionRequest{ActorUserId: "user1"}), "users can't pick another actor's budget")
+	assert.Equal(t, "user:user1", rateLimitKey(internal, &pb.PutDecisionRequest{ActorUserId: "user1"}))
+	assert.Equal(t, "user:user2", rateLimitKey(internal, &pb.GetQuotaRequest{UserId: "user2"}))
+	assert.Equal(t, "principal:backend", rateLimitKey(internal, &pb.CountLikedYouRequest{}))
+	assert.Equal(t, "user:user1", rateLimitKey(context.Background(), &pb.PutDecisionRequest{ActorUserId: "user1"}))
+	assert.Equal(t, "user:user2", rateLimitKey(context.Background(), &pb.CountLikedYouRequest{RecipientUserId: "user2"}))
+	assert.Equal(t, "anonymous", rateLimitKey(context.Background(), &pb.CountLikedYouRequest{}))
+}
+
+type staticAuthenticator struct {
+	principal *auth.Principal
+}
+
+func (a staticAuthenticator) Authenticate(ctx context.Context) (*auth.Principal, error) {
+	if a.principal == nil {
+		return nil, status.Error(codes.Unauthenticated, "no credentials")
+	}
+	return a.principal, nil
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	interceptor := authInterceptor(staticAuthenticator{})
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/explore.ExploreService/CountLikedYou"}, okHandler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Health checks don't need credentials
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, okHandler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	interceptor = authInterceptor(staticAuthenticator{principal: &auth.Principal{Subject: "user1"}})
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/explore.ExploreService/CountLikedYou"},
+		func(ctx context.Context, req any) (any, error) {
+			p, ok := auth.PrincipalFromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, "user1", p.Subject)
+			return nil, nil
+		})
+	assert.NoError(t, err)
+}
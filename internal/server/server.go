@@ -1,30 +1,199 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"net"
+	"net/http"
+	"sync"
 
+	"connectrpc.com/vanguard/vanguardgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/logging"
+	"muzz-explore-service/internal/metrics"
+	"muzz-explore-service/internal/ratelimit"
 	"muzz-explore-service/internal/service"
 	pb "muzz-explore-service/pkg/pb/proto"
 )
 
+// inProcessBufferSize is the buffer of the in-memory connection used by local protocol gateways
+const inProcessBufferSize = 1 << 20
+
 type Server struct {
 	grpcServer *grpc.Server
+	health     *health.Server
+
+	// Serves gateways in the same process over an in-memory connection, with
+	// the same interceptors as grpcServer but without transport security
+	inProcess         *grpc.Server
+	inProcessListener *bufconn.Listener
+	inProcessOnce     sync.Once
+}
+
+// Option configures optional server features
+type Option func(*options)
+
+type options struct {
+	metrics        *metrics.Metrics
+	tracerProvider trace.TracerProvider
+	logger         *slog.Logger
+	redactor       logging.UserIDRedactor
+	authenticator  auth.Authenticator
+	tlsConfig      *tls.Config
+	rateLimiter    *ratelimit.Limiter
+	maxRecvMsgSize int
+}
+
+// WithMetrics records per-RPC Prometheus metrics
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithTracing starts a span for each RPC using the given provider
+func WithTracing(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithLogging attaches a request-scoped logger to each RPC and writes an access
+// log, with user IDs in requests rendered by the given redactor
+func WithLogging(logger *slog.Logger, redactor logging.UserIDRedactor) Option {
+	return func(o *options) {
+		o.logger = logger
+		o.redactor = redactor
+	}
+}
+
+// WithAuthenticator requires every RPC, except health checks, to authenticate
+func WithAuthenticator(authenticator auth.Authenticator) Option {
+	return func(o *options) {
+		o.authenticator = authenticator
+	}
+}
+
+// WithTLS serves over TLS instead of plain TCP
+func WithTLS(config *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = config
+	}
+}
+
+// WithRateLimiter limits how often each caller may invoke each RPC
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithMaxRecvMsgSize sets the largest request message, in bytes, the server accepts
+func WithMaxRecvMsgSize(size int) Option {
+	return func(o *options) {
+		o.maxRecvMsgSize = size
+	}
 }
 
-func NewGRPCServer(service *service.ExploreService) *Server {
-	server := grpc.NewServer()
+func NewGRPCServer(service *service.ExploreService, opts ...Option) *Server {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var interceptors []grpc.UnaryServerInterceptor
+	if o.tracerProvider != nil {
+		interceptors = append(interceptors, tracingInterceptor(o.tracerProvider))
+	}
+	if o.logger != nil {
+		interceptors = append(interceptors, loggingInterceptor(o.logger, o.redactor))
+	}
+	if o.metrics != nil {
+		interceptors = append(interceptors, metricsInterceptor(o.metrics))
+	}
+	if o.authenticator != nil {
+		interceptors = append(interceptors, authInterceptor(o.authenticator))
+	}
+	if o.rateLimiter != nil {
+		logger := o.logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		interceptors = append(interceptors, rateLimitInterceptor(o.rateLimiter, logger))
+	}
+
+	serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+	if o.maxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(o.maxRecvMsgSize))
+	}
+	inProcess := grpc.NewServer(serverOpts...)
+	pb.RegisterExploreServiceServer(inProcess, service)
+
+	if o.tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(o.tlsConfig)))
+	}
+
+	server := grpc.NewServer(serverOpts...)
 	pb.RegisterExploreServiceServer(server, service)
 
+	// Report NOT_SERVING until a readiness probe confirms the dependencies are up
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(pb.ExploreService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
 	// Enable reflection
 	reflection.Register(server)
 
 	return &Server{
-		grpcServer: server,
+		grpcServer:        server,
+		health:            healthServer,
+		inProcess:         inProcess,
+		inProcessListener: bufconn.Listen(inProcessBufferSize),
+	}
+}
+
+// DialInProcess returns a client connection to the ExploreService that never
+// leaves the process, for gateways translating other protocols to gRPC.
+// Calls still pass through every interceptor, so callers must forward credentials.
+func (s *Server) DialInProcess() (*grpc.ClientConn, error) {
+	s.inProcessOnce.Do(func() {
+		go s.inProcess.Serve(s.inProcessListener)
+	})
+
+	return grpc.NewClient("passthrough:///in-process",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.inProcessListener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+// ConnectHandler returns an HTTP handler serving the ExploreService over the
+// Connect, gRPC-Web and gRPC protocols, transcoded to gRPC so calls pass
+// through the same interceptors as native gRPC calls
+func (s *Server) ConnectHandler() (http.Handler, error) {
+	transcoder, err := vanguardgrpc.NewTranscoder(s.inProcess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcoder: %w", err)
 	}
+	return transcoder, nil
+}
+
+// Health returns the server's gRPC health service, for readiness probes to update
+func (s *Server) Health() *health.Server {
+	return s.health
 }
 
 func (s *Server) Start(port int) error {
@@ -33,9 +202,18 @@ func (s *Server) Start(port int) error {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
+	return s.Serve(lis)
+}
+
+// Serve serves gRPC, with the server's transport security, on lis until the server stops
+func (s *Server) Serve(lis net.Listener) error {
 	return s.grpcServer.Serve(lis)
 }
 
+// GracefulStop marks the server NOT_SERVING, so load balancers stop routing new
+// requests to it, then waits for in-flight RPCs to finish
 func (s *Server) GracefulStop() {
+	s.health.Shutdown()
 	s.grpcServer.GracefulStop()
+	s.inProcess.GracefulStop()
 }
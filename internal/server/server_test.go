@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"muzz-explore-service/internal/service"
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+func TestConnectHandler(t *testing.T) {
+	srv := NewGRPCServer(service.NewExploreService(nil))
+	handler, err := srv.ConnectHandler()
+	require.NoError(t, err)
+
+	// Connect unary call with a JSON body
+	req := httptest.NewRequest(http.MethodPost, "/explore.ExploreService/PutDecision", strings.NewReader(`{"actorUserId": "user1", "recipientUserId": "user1", "likedRecipient": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"invalid_argument"`)
+	assert.Contains(t, rec.Body.String(), "users can't like themselves")
+
+	// gRPC-Web call with a length-prefixed protobuf message
+	msg, err := proto.Marshal(&pb.CountLikedYouRequest{})
+	require.NoError(t, err)
+	var body bytes.Buffer
+	body.WriteByte(0)
+	require.NoError(t, binary.Write(&body, binary.BigEndian, uint32(len(msg))))
+	body.Write(msg)
+
+	req = httptest.NewRequest(http.MethodPost, "/explore.ExploreService/CountLikedYou", &body)
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "3", rec.Header().Get("Grpc-Status")) // INVALID_ARGUMENT, as recipient_user_id is required
+	assert.Equal(t, "recipient_user_id is required", rec.Header().Get("Grpc-Message"))
+}
@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/loadgen"
+	"muzz-explore-service/internal/server"
+	"muzz-explore-service/internal/service"
+	pb "muzz-explore-service/pkg/pb/proto"
+)
+
+// memQueries keeps decisions in memory, implementing the queries behind the
+// four core RPCs so benchmarks measure the service and gRPC stack alone
+type memQueries struct {
+	db.Querier
+
+	mu        sync.RWMutex
+	nextID    int64
+	decisions map[[2]string]*memDecision
+	likers    map[string][]*memDecision // By recipient
+}
+
+type memDecision struct {
+	actor, recipient string
+	liked, superLike bool
+	createdAt        time.Time
+	id               int64
+}
+
+func newMemQueries() *memQueries {
+	return &memQueries{
+		decisions: make(map[[2]string]*memDecision),
+		likers:    make(map[string][]*memDecision),
+	}
+}
+
+func (m *memQueries) PutDecision(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]string{arg.ActorUserID, arg.RecipientUserID}
+	d, ok := m.decisions[key]
+	if !ok {
+		m.nextID++
+		d = &memDecision{actor: arg.ActorUserID, recipient: arg.RecipientUserID, createdAt: time.Now(), id: m.nextID}
+		m.decisions[key] = d
+		m.likers[arg.RecipientUserID] = append(m.likers[arg.RecipientUserID], d)
+	}
+	d.liked, d.superLike = arg.Liked, arg.SuperLike
+
+	reverse, ok := m.decisions[[2]string{arg.RecipientUserID, arg.ActorUserID}]
+	return ok && reverse.liked, nil
+}
+
+// InTx runs fn directly, as each query already holds the lock it needs
+func (m *memQueries) InTx(ctx context.Context, fn func(db.Querier) error) error {
+	return fn(m)
+}
+
+func (m *memQueries) LockActorDecisions(ctx context.Context, actorUserID string) error {
+	return nil
+}
+
+// listLikers returns the recipient's likes newest first, after the cursor
+func (m *memQueries) listLikers(arg db.ListLikersParams, newOnly bool) []db.ListLikersRow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rows []db.ListLikersRow
+	decisions := m.likers[arg.RecipientUserID]
+	for i := len(decisions) - 1; i >= 0 && len(rows) < int(arg.PageLimit); i-- {
+		d := decisions[i]
+		if !d.liked || (d.superLike && !arg.IncludeSuper) || (!d.superLike && !arg.IncludeRegular) {
+			continue
+		}
+		if !arg.CreatedAtCursor.IsZero() && d.id >= arg.IDCursor {
+			continue
+		}
+		if newOnly {
+			if reverse, ok := m.decisions[[2]string{d.recipient, d.actor}]; ok && reverse.liked {
+				continue
+			}
+		}
+		rows = append(rows, db.ListLikersRow{ActorUserID: d.actor, CreatedAt: d.createdAt, ID: d.id, SuperLike: d.superLike})
+	}
+	return rows
+}
+
+func (m *memQueries) ListLikers(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error) {
+	return m.listLikers(arg, false), nil
+}
+
+func (m *memQueries) ListNewLikers(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
+	rows := m.listLikers(db.ListLikersParams(arg), true)
+	converted := make([]db.ListNewLikersRow, len(rows))
+	for i, row := range rows {
+		converted[i] = db.ListNewLikersRow(row)
+	}
+	return converted, nil
+}
+
+func (m *memQueries) CountLikers(ctx context.Context, recipientUserID string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, d := range m.likers[recipientUserID] {
+		if d.liked {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// benchClient serves the service in process, through the same gRPC server and
+// interceptors as production, seeded with decisions from a Zipfian workload
+func benchClient(b *testing.B) (pb.ExploreServiceClient, *loadgen.Workload) {
+	b.Helper()
+
+	queries := newMemQueries()
+	workload, err := loadgen.NewWorkload(10000, 1.1, 0.5, loadgen.Mix{loadgen.OpPutDecision: 1}, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for range 50000 {
+		call := workload.Next()
+		queries.PutDecision(context.Background(), db.PutDecisionParams{
+			ActorUserID:     call.Actor,
+			RecipientUserID: call.Recipient,
+			Liked:           call.Liked,
+		})
+	}
+
+	srv := server.NewGRPCServer(service.NewExploreService(queries))
+	conn, err := srv.DialInProcess()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		conn.Close()
+		srv.GracefulStop()
+	})
+
+	return pb.NewExploreServiceClient(conn), workload
+}
+
+func BenchmarkPutDecision(b *testing.B) {
+	client, workload := benchClient(b)
+	var seq atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			n := seq.Add(1)
+			_, err := client.PutDecision(context.Background(), &pb.PutDecisionRequest{
+				ActorUserId:     "bench-actor-" + strconv.FormatInt(n, 10),
+				RecipientUserId: workload.User(int(n % 100)),
+				LikedRecipient:  n%2 == 0,
+			})
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// benchmarkRead calls op against the most popular recipients, which have the most likes
+func benchmarkRead(b *testing.B, op loadgen.Op) {
+	client, workload := benchClient(b)
+	var seq atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			n := seq.Add(1)
+			call := loadgen.Call{Op: op, Recipient: workload.User(int(n % 100))}
+			if err := loadgen.Do(context.Background(), client, call); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkListLikedYou(b *testing.B) {
+	benchmarkRead(b, loadgen.OpListLikedYou)
+}
+
+func BenchmarkListNewLikedYou(b *testing.B) {
+	benchmarkRead(b, loadgen.OpListNewLikedYou)
+}
+
+func BenchmarkCountLikedYou(b *testing.B) {
+	benchmarkRead(b, loadgen.OpCountLikedYou)
+}
+
+// BenchmarkMixedWorkload replays the load generator's default traffic mix
+func BenchmarkMixedWorkload(b *testing.B) {
+	client, _ := benchClient(b)
+	var seed atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		workload, err := loadgen.NewWorkload(10000, 1.1, 0.4, loadgen.DefaultMix, seed.Add(1))
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		for p.Next() {
+			if err := loadgen.Do(context.Background(), client, workload.Next()); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
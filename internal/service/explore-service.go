@@ -4,27 +4,137 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"log"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"log/slog"
+	"muzz-explore-service/internal/auth"
 	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/deck"
+	"muzz-explore-service/internal/expiry"
+	"muzz-explore-service/internal/logging"
+	"muzz-explore-service/internal/quota"
+	"muzz-explore-service/internal/scoring"
+	"muzz-explore-service/internal/visibility"
 	pb "muzz-explore-service/pkg/pb/proto"
 	"time"
 )
 
 const pageSize = 50
 
+const defaultDeckSize = 20
+
+// DecisionObserver is notified of every decision successfully recorded, with
+// the decision it replaced, nil for a first decision
+type DecisionObserver interface {
+	ObserveDecision(liked bool, previous *bool, mutual bool)
+}
+
+type noopObserver struct{}
+
+func (noopObserver) ObserveDecision(bool, *bool, bool) {}
+
 type ExploreService struct {
 	pb.UnimplementedExploreServiceServer
-	queries db.Querier
+	queries  db.Store
+	observer DecisionObserver
+	logger   *slog.Logger
+
+	// When set, callers may only act as themselves unless they are an internal service
+	authorize bool
+
+	// Like quota for free tier users, nil if likes are unlimited
+	quota *quota.Manager
+
+	// Hides liker identities from recipients not entitled to see them, nil if everyone sees them
+	visibility *visibility.Policy
+
+	// Pools GetExploreDeck draws candidates from, nil if the deck isn't served
+	candidates deck.CandidateSource
+
+	// Keeps desirability scores up to date with each decision, nil if scores aren't maintained
+	scorer *scoring.Scorer
+
+	// Age after which unanswered likes drop out of new likes and counts, 0 if likes never expire
+	likeExpiry time.Duration
+}
+
+// Option configures optional ExploreService dependencies
+type Option func(*ExploreService)
+
+// WithDecisionObserver sets the observer notified of recorded decisions
+func WithDecisionObserver(observer DecisionObserver) Option {
+	return func(s *ExploreService) {
+		s.observer = observer
+	}
+}
+
+// WithLogger sets the logger used when a request carries no request-scoped logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *ExploreService) {
+		s.logger = logger
+	}
+}
+
+// WithAuthorization requires the authenticated principal to match the actor of
+// PutDecision, or the recipient of the list and count RPCs
+func WithAuthorization() Option {
+	return func(s *ExploreService) {
+		s.authorize = true
+	}
+}
+
+// WithQuota caps how many new likes free tier users can make per quota window
+func WithQuota(q *quota.Manager) Option {
+	return func(s *ExploreService) {
+		s.quota = q
+	}
+}
+
+// WithVisibilityPolicy redacts the likers returned to recipients the policy doesn't entitle
+func WithVisibilityPolicy(p *visibility.Policy) Option {
+	return func(s *ExploreService) {
+		s.visibility = p
+	}
+}
+
+// WithCandidateSource serves explore decks drawn from the source's candidate pools
+func WithCandidateSource(source deck.CandidateSource) Option {
+	return func(s *ExploreService) {
+		s.candidates = source
+	}
 }
 
-func NewExploreService(queries db.Querier) *ExploreService {
-	return &ExploreService{
-		queries: queries,
+// WithScorer updates the recipient's desirability score on every decision and serves GetUserScore
+func WithScorer(scorer *scoring.Scorer) Option {
+	return func(s *ExploreService) {
+		s.scorer = scorer
 	}
 }
 
+// WithLikeExpiry hides likes older than maxAge from ListNewLikedYou and the new and unseen like counts
+func WithLikeExpiry(maxAge time.Duration) Option {
+	return func(s *ExploreService) {
+		s.likeExpiry = maxAge
+	}
+}
+
+func NewExploreService(queries db.Store, opts ...Option) *ExploreService {
+	s := &ExploreService{
+		queries:  queries,
+		observer: noopObserver{},
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 // PutDecision records a user's decision to like or pass another user
 // Returns whether this creates a mutual like between the users
 func (s *ExploreService) PutDecision(ctx context.Context, req *pb.PutDecisionRequest) (*pb.PutDecisionResponse, error) {
@@ -32,19 +142,66 @@ func (s *ExploreService) PutDecision(ctx context.Context, req *pb.PutDecisionReq
 		return nil, status.Error(codes.InvalidArgument, "both actor_user_id and recipient_user_id are required")
 	}
 
+	if req.SuperLike && !req.LikedRecipient {
+		return nil, status.Error(codes.InvalidArgument, "super_like requires liked_recipient")
+	}
+
 	// Prevent self-liking
 	if req.ActorUserId == req.RecipientUserId {
 		return nil, status.Error(codes.InvalidArgument, "users can't like themselves")
 	}
 
-	mutualLikes, err := s.queries.PutDecision(ctx, db.PutDecisionParams{
-		ActorUserID:     req.ActorUserId,
-		RecipientUserID: req.RecipientUserId,
-		Liked:           req.LikedRecipient,
+	if err := s.authorizeUser(ctx, req.ActorUserId); err != nil {
+		return nil, err
+	}
+
+	var (
+		mutualLikes bool
+		previous    *bool
+	)
+	err := s.queries.InTx(ctx, func(q db.Querier) error {
+		// Serialise the actor's decisions, so each sees the previous one and
+		// concurrent likes see each other's quota usage
+		if err := q.LockActorDecisions(ctx, req.ActorUserId); err != nil {
+			return fmt.Errorf("failed to lock actor decisions: %w", err)
+		}
+		var err error
+		previous, err = s.previousDecision(ctx, q, req)
+		if err != nil {
+			return err
+		}
+		if err := s.claimQuota(ctx, q, req, previous); err != nil {
+			return err
+		}
+
+		mutualLikes, err = q.PutDecision(ctx, db.PutDecisionParams{
+			ActorUserID:     req.ActorUserId,
+			RecipientUserID: req.RecipientUserId,
+			Liked:           req.LikedRecipient,
+			SuperLike:       req.SuperLike,
+		})
+		if err != nil {
+			return err
+		}
+
+		if s.scorer == nil {
+			return nil
+		}
+		return s.scorer.Apply(ctx, q, scoring.Change{
+			ActorUserID:     req.ActorUserId,
+			RecipientUserID: req.RecipientUserId,
+			Liked:           req.LikedRecipient,
+			Previous:        previous,
+		})
 	})
+	if status.Code(err) == codes.ResourceExhausted {
+		return nil, err
+	}
 	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to record decision", "error", err)
 		return nil, status.Error(codes.Internal, "failed to record decision")
 	}
+	s.observer.ObserveDecision(req.LikedRecipient, previous, mutualLikes)
 
 	return &pb.PutDecisionResponse{
 		MutualLikes: mutualLikes,
@@ -54,32 +211,89 @@ func (s *ExploreService) PutDecision(ctx context.Context, req *pb.PutDecisionReq
 // ListLikedYou returns a list of users who have liked the recipient
 // Supports pagination using cursor-based pagination for efficiency
 func (s *ExploreService) ListLikedYou(ctx context.Context, req *pb.ListLikedYouRequest) (*pb.ListLikedYouResponse, error) {
+	return s.listLikers(ctx, req, false)
+}
+
+// ListNewLikedYou returns a list of users who have liked the recipient but haven't been liked back
+// Similar to ListLikedYou but excludes mutual likes
+func (s *ExploreService) ListNewLikedYou(ctx context.Context, req *pb.ListLikedYouRequest) (*pb.ListLikedYouResponse, error) {
+	return s.listLikers(ctx, req, true)
+}
+
+// listLikers implements the liker list RPCs, excluding mutual likes if newOnly is set
+func (s *ExploreService) listLikers(ctx context.Context, req *pb.ListLikedYouRequest, newOnly bool) (*pb.ListLikedYouResponse, error) {
 	if req.RecipientUserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "recipient_user_id is required")
 	}
 
-	cursorTime, err := s.decodePaginationToken(req.PaginationToken)
+	if err := s.authorizeUser(ctx, req.RecipientUserId); err != nil {
+		return nil, err
+	}
+
+	oldestFirst := req.SortOrder == pb.SortOrder_SORT_ORDER_OLDEST_FIRST
+	cursor, err := s.decodePaginationToken(req.PaginationToken, oldestFirst)
 	if err != nil {
 		return nil, err
 	}
 
 	params := db.ListLikersParams{
 		RecipientUserID: req.RecipientUserId,
-		CreatedAtCursor: cursorTime,
+		IncludeRegular:  req.LikeType != pb.LikeType_LIKE_TYPE_SUPER,
+		IncludeSuper:    req.LikeType != pb.LikeType_LIKE_TYPE_REGULAR,
+		CreatedAtCursor: cursor.createdAt(),
+		IDCursor:        cursor.ID,
 		PageLimit:       int32(pageSize + 1), // Fetch one extra item to check for next page
 	}
+	if req.SinceUnixTimestamp != nil {
+		params.Since = time.Unix(int64(*req.SinceUnixTimestamp), 0)
+	}
+	if req.UntilUnixTimestamp != nil {
+		params.Until = time.Unix(int64(*req.UntilUnixTimestamp), 0)
+		if !params.Until.After(params.Since) {
+			return nil, status.Error(codes.InvalidArgument, "until_unix_timestamp must be after since_unix_timestamp")
+		}
+	}
+
+	what := "likers"
+	if newOnly {
+		what = "new likers"
+		// Expired likes are left out by raising the lower time bound
+		if expiredBefore := s.expiredBefore(); expiredBefore.After(params.Since) {
+			params.Since = expiredBefore
+		}
+	}
+
+	// count totals every liker the filters match, across all pages
+	count := func(ctx context.Context) (int64, error) {
+		filters := db.CountFilteredLikersParams{
+			RecipientUserID: params.RecipientUserID,
+			IncludeRegular:  params.IncludeRegular,
+			IncludeSuper:    params.IncludeSuper,
+			Since:           params.Since,
+			Until:           params.Until,
+		}
+		if newOnly {
+			return s.queries.CountNewLikers(ctx, db.CountNewLikersParams(filters))
+		}
+		return s.queries.CountFilteredLikers(ctx, filters)
+	}
 
-	decisions, err := s.queries.ListLikers(ctx, params)
+	decisions, err := s.fetchLikers(ctx, params, newOnly, oldestFirst)
 	if err != nil {
-		log.Printf("Error fetching decisions: %v", err)
-		return nil, status.Error(codes.Internal, "failed to fetch likers")
+		s.log(ctx).ErrorContext(ctx, "failed to fetch "+what, "error", err)
+		return nil, status.Error(codes.Internal, "failed to fetch "+what)
 	}
-	log.Printf("Found %d decisions", len(decisions))
+	s.log(ctx).DebugContext(ctx, "fetched "+what, "count", len(decisions))
 
 	// Generate next page token if we have more results
 	var nextToken string
 	if len(decisions) > pageSize {
-		nextToken, err = s.generateNextToken(decisions[pageSize-1].CreatedAt)
+		last := decisions[pageSize-1]
+		nextToken, err = s.generateNextToken(pageCursor{
+			CreatedAt:   last.CreatedAt.UnixMicro(),
+			ID:          last.ID,
+			OldestFirst: oldestFirst,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -92,75 +306,128 @@ func (s *ExploreService) ListLikedYou(ctx context.Context, req *pb.ListLikedYouR
 		likers[i] = &pb.ListLikedYouResponse_Liker{
 			ActorId:       d.ActorUserID,
 			UnixTimestamp: uint64(d.CreatedAt.Unix()),
+			SuperLike:     d.SuperLike,
+			Seen:          d.Seen,
 		}
 	}
 
-	return &pb.ListLikedYouResponse{
+	resp := &pb.ListLikedYouResponse{
 		Likers:              likers,
 		NextPaginationToken: &nextToken,
-	}, nil
+	}
+	if err := s.redactLikers(ctx, req.RecipientUserId, resp, count); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
-// ListNewLikedYou returns a list of users who have liked the recipient but haven't been liked back
-// Similar to ListLikedYou but excludes mutual likes
-func (s *ExploreService) ListNewLikedYou(ctx context.Context, req *pb.ListLikedYouRequest) (*pb.ListLikedYouResponse, error) {
+// CountLikedYou returns the total number of users who have liked the recipient
+func (s *ExploreService) CountLikedYou(ctx context.Context, req *pb.CountLikedYouRequest) (*pb.CountLikedYouResponse, error) {
 	if req.RecipientUserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "recipient_user_id is required")
 	}
 
-	cursorTime, err := s.decodePaginationToken(req.PaginationToken)
-	if err != nil {
+	if err := s.authorizeUser(ctx, req.RecipientUserId); err != nil {
 		return nil, err
 	}
 
-	params := db.ListNewLikersParams{
-		RecipientUserID: req.RecipientUserId,
-		CreatedAtCursor: cursorTime,
-		PageLimit:       int32(pageSize + 1), // Fetch one extra item to check for next page
+	count, err := s.queries.CountLikers(ctx, req.RecipientUserId)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to count likers", "error", err)
+		return nil, status.Error(codes.Internal, "failed to count likers")
 	}
-	log.Printf("Query params: %+v", params)
 
-	decisions, err := s.queries.ListNewLikers(ctx, params)
-	if err != nil {
-		log.Printf("Error fetching decisions: %v", err)
-		return nil, status.Error(codes.Internal, "failed to fetch new likers")
+	return &pb.CountLikedYouResponse{
+		Count: uint64(count),
+	}, nil
+}
+
+// GetUserScore returns a user's desirability score for ranking
+func (s *ExploreService) GetUserScore(ctx context.Context, req *pb.GetUserScoreRequest) (*pb.GetUserScoreResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
-	log.Printf("Found %d decisions", len(decisions))
 
-	// Generate next page token if we have more results
-	var nextToken string
-	if len(decisions) > pageSize {
-		nextToken, err = s.generateNextToken(decisions[pageSize-1].CreatedAt)
-		if err != nil {
+	if s.authorize {
+		if err := auth.AuthorizeInternal(ctx); err != nil {
 			return nil, err
 		}
-		decisions = decisions[:pageSize] // Truncate decisions to the page size
 	}
 
-	// Convert database results to protobuf response format
-	likers := make([]*pb.ListLikedYouResponse_Liker, len(decisions))
-	for i, d := range decisions {
-		likers[i] = &pb.ListLikedYouResponse_Liker{
-			ActorId:       d.ActorUserID,
-			UnixTimestamp: uint64(d.CreatedAt.Unix()),
+	if s.scorer == nil {
+		return nil, status.Error(codes.FailedPrecondition, "user scores are not enabled")
+	}
+
+	score, err := s.scorer.Get(ctx, req.UserId)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to get user score", "error", err)
+		return nil, status.Error(codes.Internal, "failed to get user score")
+	}
+
+	resp := &pb.GetUserScoreResponse{
+		Score:          score.Score,
+		LikesReceived:  uint64(score.LikesReceived),
+		PassesReceived: uint64(score.PassesReceived),
+	}
+	if !score.UpdatedAt.IsZero() {
+		resp.UpdatedUnixTimestamp = uint64(score.UpdatedAt.Unix())
+	}
+	return resp, nil
+}
+
+// MarkLikesSeen moves the recipient's seen watermark forward. Likes made
+// before the watermark are flagged as seen and left out of CountUnseenLikedYou.
+func (s *ExploreService) MarkLikesSeen(ctx context.Context, req *pb.MarkLikesSeenRequest) (*pb.MarkLikesSeenResponse, error) {
+	if req.RecipientUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "recipient_user_id is required")
+	}
+
+	if err := s.authorizeUser(ctx, req.RecipientUserId); err != nil {
+		return nil, err
+	}
+
+	// The watermark is exclusive, while the API deals in whole seconds. It
+	// never moves backwards, so a future watermark can't be undone.
+	seenBefore := time.Now()
+	if req.SeenUntilUnixTimestamp != nil {
+		if *req.SeenUntilUnixTimestamp > uint64(seenBefore.Unix()) {
+			return nil, status.Error(codes.InvalidArgument, "seen_until_unix_timestamp can't be in the future")
 		}
+		seenBefore = time.Unix(int64(*req.SeenUntilUnixTimestamp)+1, 0)
 	}
 
-	return &pb.ListLikedYouResponse{
-		Likers:              likers,
-		NextPaginationToken: &nextToken,
+	watermark, err := s.queries.MarkLikesSeen(ctx, db.MarkLikesSeenParams{
+		RecipientUserID: req.RecipientUserId,
+		SeenBefore:      seenBefore,
+	})
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to mark likes seen", "error", err)
+		return nil, status.Error(codes.Internal, "failed to mark likes seen")
+	}
+
+	return &pb.MarkLikesSeenResponse{
+		SeenBeforeUnixTimestamp: uint64(watermark.Unix()),
 	}, nil
 }
 
-// CountLikedYou returns the total number of users who have liked the recipient
-func (s *ExploreService) CountLikedYou(ctx context.Context, req *pb.CountLikedYouRequest) (*pb.CountLikedYouResponse, error) {
+// CountUnseenLikedYou returns the number of users who liked the recipient since their seen watermark
+func (s *ExploreService) CountUnseenLikedYou(ctx context.Context, req *pb.CountLikedYouRequest) (*pb.CountLikedYouResponse, error) {
 	if req.RecipientUserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "recipient_user_id is required")
 	}
 
-	count, err := s.queries.CountLikers(ctx, req.RecipientUserId)
+	if err := s.authorizeUser(ctx, req.RecipientUserId); err != nil {
+		return nil, err
+	}
+
+	count, err := s.queries.CountUnseenLikers(ctx, db.CountUnseenLikersParams{
+		RecipientUserID: req.RecipientUserId,
+		ExpiredBefore:   s.expiredBefore(),
+	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to count likers")
+		s.log(ctx).ErrorContext(ctx, "failed to count unseen likers", "error", err)
+		return nil, status.Error(codes.Internal, "failed to count unseen likers")
 	}
 
 	return &pb.CountLikedYouResponse{
@@ -168,31 +435,282 @@ func (s *ExploreService) CountLikedYou(ctx context.Context, req *pb.CountLikedYo
 	}, nil
 }
 
-// decodePaginationToken decodes a base64-encoded pagination token into a timestamp.
+// authorizeUser checks the caller may act as userID when authorization is enabled
+func (s *ExploreService) authorizeUser(ctx context.Context, userID string) error {
+	if !s.authorize {
+		return nil
+	}
+	return auth.AuthorizeUser(ctx, userID)
+}
+
+// expiredBefore returns the creation time before which likes are expired, zero if they never expire
+func (s *ExploreService) expiredBefore() time.Time {
+	return expiry.ExpiredBefore(time.Now(), s.likeExpiry)
+}
+
+// log returns the request-scoped logger, falling back to the service logger
+func (s *ExploreService) log(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
+// redactLikers replaces liker identities with opaque handles, and adds the
+// total number of likers matching the list's filters, when the recipient isn't
+// entitled to see who liked them
+func (s *ExploreService) redactLikers(ctx context.Context, recipientUserID string, resp *pb.ListLikedYouResponse, count func(context.Context) (int64, error)) error {
+	if s.visibility == nil {
+		return nil
+	}
+
+	canSee, err := s.visibility.CanSeeLikers(ctx, recipientUserID)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to check likes visibility", "error", err)
+		return status.Error(codes.Internal, "failed to check likes visibility")
+	}
+	if canSee {
+		return nil
+	}
+
+	total, err := count(ctx)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to count likers", "error", err)
+		return status.Error(codes.Internal, "failed to count likers")
+	}
+
+	for _, liker := range resp.Likers {
+		liker.Handle = s.visibility.Handle(recipientUserID, liker.ActorId)
+		liker.ActorId = ""
+	}
+	resp.Redacted = true
+	resp.TotalCount = uint64(total)
+	return nil
+}
+
+// GetQuota returns how many likes the user has left in the current quota window
+func (s *ExploreService) GetQuota(ctx context.Context, req *pb.GetQuotaRequest) (*pb.GetQuotaResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.authorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if s.quota == nil {
+		return &pb.GetQuotaResponse{Unlimited: true}, nil
+	}
+
+	quotaStatus, err := s.quota.Status(ctx, req.UserId)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to get like quota", "error", err)
+		return nil, status.Error(codes.Internal, "failed to get quota")
+	}
+
+	resp := &pb.GetQuotaResponse{
+		Unlimited: quotaStatus.Unlimited,
+		Limit:     uint32(quotaStatus.Limit),
+		Remaining: uint32(quotaStatus.Remaining),
+	}
+	if !quotaStatus.ResetAt.IsZero() {
+		resp.ResetUnixTimestamp = uint64(quotaStatus.ResetAt.Unix())
+	}
+	return resp, nil
+}
+
+// GetExploreDeck returns the next candidates the actor hasn't decided on yet,
+// in pool order, with users who already liked the actor first within each page
+func (s *ExploreService) GetExploreDeck(ctx context.Context, req *pb.GetExploreDeckRequest) (*pb.GetExploreDeckResponse, error) {
+	if req.ActorUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "actor_user_id is required")
+	}
+
+	if err := s.authorizeUser(ctx, req.ActorUserId); err != nil {
+		return nil, err
+	}
+
+	if s.candidates == nil {
+		return nil, status.Error(codes.FailedPrecondition, "explore deck is not configured")
+	}
+
+	cursor, err := deck.DecodeCursor(req.GetPaginationToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid pagination token")
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultDeckSize
+	}
+	limit = min(limit, pageSize)
+
+	pool, err := s.candidates.Candidates(ctx, req.ActorUserId)
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to fetch candidates", "error", err)
+		return nil, status.Error(codes.Internal, "failed to fetch candidates")
+	}
+	pool = deck.Dedupe(pool, req.ActorUserId)
+
+	decided, err := s.queries.ListDecidedRecipients(ctx, db.ListDecidedRecipientsParams{
+		ActorUserID:      req.ActorUserId,
+		CandidateUserIds: pool,
+	})
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to fetch decided candidates", "error", err)
+		return nil, status.Error(codes.Internal, "failed to fetch candidates")
+	}
+
+	likers, err := s.queries.ListLikersAmong(ctx, db.ListLikersAmongParams{
+		RecipientUserID:  req.ActorUserId,
+		CandidateUserIds: pool,
+	})
+	if err != nil {
+		s.log(ctx).ErrorContext(ctx, "failed to fetch likers among candidates", "error", err)
+		return nil, status.Error(codes.Internal, "failed to fetch candidates")
+	}
+
+	userIDs, next := deck.Page(pool, toSet(decided), toSet(likers), cursor, limit)
+	s.log(ctx).DebugContext(ctx, "built explore deck", "pool", len(pool), "decided", len(decided), "count", len(userIDs))
+
+	var nextToken string
+	if next != nil {
+		nextToken = next.Encode()
+	}
+
+	candidates := make([]*pb.GetExploreDeckResponse_Candidate, len(userIDs))
+	for i, userID := range userIDs {
+		candidates[i] = &pb.GetExploreDeckResponse_Candidate{UserId: userID}
+	}
+
+	return &pb.GetExploreDeckResponse{
+		Candidates:          candidates,
+		NextPaginationToken: &nextToken,
+	}, nil
+}
+
+func toSet(userIDs []string) map[string]bool {
+	set := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		set[id] = true
+	}
+	return set
+}
+
+// previousDecision returns whether the actor liked the recipient before, nil if
+// they hadn't decided
+func (s *ExploreService) previousDecision(ctx context.Context, q db.Querier, req *pb.PutDecisionRequest) (*bool, error) {
+	liked, err := q.GetDecision(ctx, db.GetDecisionParams{
+		ActorUserID:     req.ActorUserId,
+		RecipientUserID: req.RecipientUserId,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch previous decision: %w", err)
+	}
+	return &liked, nil
+}
+
+// claimQuota rejects new likes from users who have used up their quota, and
+// counts the rest against it. Passes and re-likes of users already liked are
+// free. Runs in the transaction recording the decision.
+func (s *ExploreService) claimQuota(ctx context.Context, q db.Querier, req *pb.PutDecisionRequest, previous *bool) error {
+	if s.quota == nil || !req.LikedRecipient || (previous != nil && *previous) {
+		return nil
+	}
+
+	quotaStatus, allowed, err := s.quota.Claim(ctx, q, req.ActorUserId, req.RecipientUserId)
+	if err != nil {
+		return fmt.Errorf("failed to claim like quota: %w", err)
+	}
+	if !allowed {
+		st := status.New(codes.ResourceExhausted, "daily like quota exceeded")
+		retry := &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Until(quotaStatus.ResetAt))}
+		if detailed, err := st.WithDetails(retry); err == nil {
+			st = detailed
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+// fetchLikers runs the liker list query matching the list and sort order
+func (s *ExploreService) fetchLikers(ctx context.Context, params db.ListLikersParams, newOnly, oldestFirst bool) ([]db.ListLikersRow, error) {
+	switch {
+	case newOnly && oldestFirst:
+		rows, err := s.queries.ListNewLikersOldestFirst(ctx, db.ListNewLikersOldestFirstParams(params))
+		return convertRows(rows), err
+	case newOnly:
+		rows, err := s.queries.ListNewLikers(ctx, db.ListNewLikersParams(params))
+		return convertRows(rows), err
+	case oldestFirst:
+		rows, err := s.queries.ListLikersOldestFirst(ctx, db.ListLikersOldestFirstParams(params))
+		return convertRows(rows), err
+	default:
+		return s.queries.ListLikers(ctx, params)
+	}
+}
+
+// likerRow is implemented by the rows of every liker list query, which all share the columns of db.ListLikersRow
+type likerRow interface {
+	db.ListLikersRow | db.ListLikersOldestFirstRow | db.ListNewLikersRow | db.ListNewLikersOldestFirstRow
+}
+
+func convertRows[T likerRow](rows []T) []db.ListLikersRow {
+	converted := make([]db.ListLikersRow, len(rows))
+	for i, row := range rows {
+		converted[i] = db.ListLikersRow(row)
+	}
+	return converted
+}
+
+// pageCursor is the position of the last liker on a page. It is keyed on
+// the decision ID rather than the liker, so tokens don't reveal who liked
+// recipients that can only see redacted likers.
+type pageCursor struct {
+	CreatedAt   int64 `json:"t"` // Unix microseconds
+	ID          int64 `json:"i"`
+	OldestFirst bool  `json:"o,omitempty"`
+}
+
+// createdAt returns the cursor time, or zero time if there is no cursor
+func (c pageCursor) createdAt() time.Time {
+	if c.CreatedAt == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(c.CreatedAt)
+}
+
+// decodePaginationToken decodes a base64-encoded pagination token into a cursor.
 // It is used to determine the starting point for cursor-based pagination.
-// Returns the decoded timestamp.
-func (s *ExploreService) decodePaginationToken(token *string) (time.Time, error) {
+// Tokens from before sort orders were supported hold a bare Unix timestamp.
+func (s *ExploreService) decodePaginationToken(token *string, oldestFirst bool) (pageCursor, error) {
 	if token == nil || *token == "" {
-		return time.Time{}, nil // Return zero time if no token is provided
+		return pageCursor{OldestFirst: oldestFirst}, nil // Start from the first page if no token is provided
 	}
 
 	data, err := base64.StdEncoding.DecodeString(*token)
 	if err != nil {
-		return time.Time{}, status.Error(codes.InvalidArgument, "invalid pagination token")
+		return pageCursor{}, status.Error(codes.InvalidArgument, "invalid pagination token")
 	}
 
+	var cursor pageCursor
 	var timestamp int64
-	if err := json.Unmarshal(data, &timestamp); err != nil {
-		return time.Time{}, status.Error(codes.InvalidArgument, "invalid pagination token")
+	if err := json.Unmarshal(data, &timestamp); err == nil {
+		cursor = pageCursor{CreatedAt: time.Unix(timestamp, 0).UnixMicro()}
+	} else if err := json.Unmarshal(data, &cursor); err != nil {
+		return pageCursor{}, status.Error(codes.InvalidArgument, "invalid pagination token")
 	}
 
-	return time.Unix(timestamp, 0), nil
+	if cursor.OldestFirst != oldestFirst {
+		return pageCursor{}, status.Error(codes.InvalidArgument, "pagination token is for a different sort order")
+	}
+	return cursor, nil
 }
 
-// generateNextToken generates a base64-encoded pagination token from a timestamp.
+// generateNextToken generates a base64-encoded pagination token from a cursor.
 // It is used to create a token for the next page of results.
-func (s *ExploreService) generateNextToken(lastTime time.Time) (string, error) {
-	data, err := json.Marshal(lastTime.Unix())
+func (s *ExploreService) generateNextToken(cursor pageCursor) (string, error) {
+	data, err := json.Marshal(cursor)
 	if err != nil {
 		return "", status.Error(codes.Internal, "failed to generate pagination token")
 	}
@@ -2,13 +2,24 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"muzz-explore-service/internal/auth"
 	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/deck"
+	"muzz-explore-service/internal/entitlements"
+	"muzz-explore-service/internal/quota"
+	"muzz-explore-service/internal/scoring"
+	"muzz-explore-service/internal/visibility"
 	pb "muzz-explore-service/pkg/pb/proto"
 )
 
@@ -17,6 +28,35 @@ type mockQueries struct {
 	listLikers    func(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error)
 	listNewLikers func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error)
 	countLikers   func(ctx context.Context, recipientUserID string) (int64, error)
+
+	countFilteredLikers func(ctx context.Context, arg db.CountFilteredLikersParams) (int64, error)
+	countNewLikers      func(ctx context.Context, arg db.CountNewLikersParams) (int64, error)
+
+	getDecision        func(ctx context.Context, arg db.GetDecisionParams) (bool, error)
+	getQuotaUsage      func(ctx context.Context, arg db.GetQuotaUsageParams) (db.GetQuotaUsageRow, error)
+	isQuotaLikeCounted func(ctx context.Context, arg db.IsQuotaLikeCountedParams) (bool, error)
+	recordQuotaLike    func(ctx context.Context, arg db.RecordQuotaLikeParams) error
+
+	listDecidedRecipients func(ctx context.Context, arg db.ListDecidedRecipientsParams) ([]string, error)
+	listLikersAmong       func(ctx context.Context, arg db.ListLikersAmongParams) ([]string, error)
+
+	getUserScore       func(ctx context.Context, userID string) (db.UserScore, error)
+	lockUserScore      func(ctx context.Context, arg db.LockUserScoreParams) (db.UserScore, error)
+	setUserScore       func(ctx context.Context, arg db.SetUserScoreParams) error
+	listDecisionsAfter func(ctx context.Context, arg db.ListDecisionsAfterParams) ([]db.ListDecisionsAfterRow, error)
+
+	listLikersOldestFirst    func(ctx context.Context, arg db.ListLikersOldestFirstParams) ([]db.ListLikersOldestFirstRow, error)
+	listNewLikersOldestFirst func(ctx context.Context, arg db.ListNewLikersOldestFirstParams) ([]db.ListNewLikersOldestFirstRow, error)
+
+	markLikesSeen     func(ctx context.Context, arg db.MarkLikesSeenParams) (time.Time, error)
+	countUnseenLikers func(ctx context.Context, arg db.CountUnseenLikersParams) (int64, error)
+
+	archiveExpiredLikes    func(ctx context.Context, arg db.ArchiveExpiredLikesParams) ([]db.ExpiredLike, error)
+	advanceLikeExpirySweep func(ctx context.Context, sweptBefore time.Time) error
+	rewindLikeExpirySweep  func(ctx context.Context, sweptBefore time.Time) error
+
+	getImportCheckpoint  func(ctx context.Context, name string) (int64, error)
+	saveImportCheckpoint func(ctx context.Context, arg db.SaveImportCheckpointParams) error
 }
 
 func (m mockQueries) PutDecision(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
@@ -35,11 +75,110 @@ func (m mockQueries) CountLikers(ctx context.Context, recipientUserID string) (i
 	return m.countLikers(ctx, recipientUserID)
 }
 
+func (m mockQueries) CountFilteredLikers(ctx context.Context, arg db.CountFilteredLikersParams) (int64, error) {
+	return m.countFilteredLikers(ctx, arg)
+}
+
+func (m mockQueries) CountNewLikers(ctx context.Context, arg db.CountNewLikersParams) (int64, error) {
+	return m.countNewLikers(ctx, arg)
+}
+
+func (m mockQueries) GetDecision(ctx context.Context, arg db.GetDecisionParams) (bool, error) {
+	return m.getDecision(ctx, arg)
+}
+
+func (m mockQueries) GetQuotaUsage(ctx context.Context, arg db.GetQuotaUsageParams) (db.GetQuotaUsageRow, error) {
+	return m.getQuotaUsage(ctx, arg)
+}
+
+func (m mockQueries) IsQuotaLikeCounted(ctx context.Context, arg db.IsQuotaLikeCountedParams) (bool, error) {
+	return m.isQuotaLikeCounted(ctx, arg)
+}
+
+func (m mockQueries) RecordQuotaLike(ctx context.Context, arg db.RecordQuotaLikeParams) error {
+	return m.recordQuotaLike(ctx, arg)
+}
+
+func (m mockQueries) ListDecidedRecipients(ctx context.Context, arg db.ListDecidedRecipientsParams) ([]string, error) {
+	return m.listDecidedRecipients(ctx, arg)
+}
+
+func (m mockQueries) ListLikersAmong(ctx context.Context, arg db.ListLikersAmongParams) ([]string, error) {
+	return m.listLikersAmong(ctx, arg)
+}
+
+func (m mockQueries) GetUserScore(ctx context.Context, userID string) (db.UserScore, error) {
+	return m.getUserScore(ctx, userID)
+}
+
+func (m mockQueries) LockUserScore(ctx context.Context, arg db.LockUserScoreParams) (db.UserScore, error) {
+	return m.lockUserScore(ctx, arg)
+}
+
+func (m mockQueries) SetUserScore(ctx context.Context, arg db.SetUserScoreParams) error {
+	return m.setUserScore(ctx, arg)
+}
+
+func (m mockQueries) ListDecisionsAfter(ctx context.Context, arg db.ListDecisionsAfterParams) ([]db.ListDecisionsAfterRow, error) {
+	return m.listDecisionsAfter(ctx, arg)
+}
+
+func (m mockQueries) ListLikersOldestFirst(ctx context.Context, arg db.ListLikersOldestFirstParams) ([]db.ListLikersOldestFirstRow, error) {
+	return m.listLikersOldestFirst(ctx, arg)
+}
+
+func (m mockQueries) ListNewLikersOldestFirst(ctx context.Context, arg db.ListNewLikersOldestFirstParams) ([]db.ListNewLikersOldestFirstRow, error) {
+	return m.listNewLikersOldestFirst(ctx, arg)
+}
+
+func (m mockQueries) MarkLikesSeen(ctx context.Context, arg db.MarkLikesSeenParams) (time.Time, error) {
+	return m.markLikesSeen(ctx, arg)
+}
+
+func (m mockQueries) CountUnseenLikers(ctx context.Context, arg db.CountUnseenLikersParams) (int64, error) {
+	return m.countUnseenLikers(ctx, arg)
+}
+
+func (m mockQueries) ArchiveExpiredLikes(ctx context.Context, arg db.ArchiveExpiredLikesParams) ([]db.ExpiredLike, error) {
+	return m.archiveExpiredLikes(ctx, arg)
+}
+
+func (m mockQueries) AdvanceLikeExpirySweep(ctx context.Context, sweptBefore time.Time) error {
+	return m.advanceLikeExpirySweep(ctx, sweptBefore)
+}
+
+func (m mockQueries) RewindLikeExpirySweep(ctx context.Context, sweptBefore time.Time) error {
+	return m.rewindLikeExpirySweep(ctx, sweptBefore)
+}
+
+func (m mockQueries) GetImportCheckpoint(ctx context.Context, name string) (int64, error) {
+	return m.getImportCheckpoint(ctx, name)
+}
+
+func (m mockQueries) SaveImportCheckpoint(ctx context.Context, arg db.SaveImportCheckpointParams) error {
+	return m.saveImportCheckpoint(ctx, arg)
+}
+
+// LockActorDecisions is a no-op, as mocks don't run concurrently
+func (m mockQueries) LockActorDecisions(ctx context.Context, actorUserID string) error {
+	return nil
+}
+
+// InTx runs fn directly, as mocks have no transactions to roll back
+func (m mockQueries) InTx(ctx context.Context, fn func(db.Querier) error) error {
+	return fn(m)
+}
+
+// noPreviousDecision mocks GetDecision for an actor who hasn't decided on the recipient yet
+func noPreviousDecision(ctx context.Context, arg db.GetDecisionParams) (bool, error) {
+	return false, pgx.ErrNoRows
+}
+
 func TestPutDecision(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *pb.PutDecisionRequest
-		mock    func() db.Querier
+		mock    func() db.Store
 		want    *pb.PutDecisionResponse
 		wantErr bool
 	}{
@@ -50,8 +189,9 @@ func TestPutDecision(t *testing.T) {
 				RecipientUserId: "user2",
 				LikedRecipient:  true,
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
+					getDecision: noPreviousDecision,
 					putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
 						return false, nil // not mutual
 					},
@@ -68,8 +208,9 @@ func TestPutDecision(t *testing.T) {
 				RecipientUserId: "user2",
 				LikedRecipient:  true,
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
+					getDecision: noPreviousDecision,
 					putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
 						return true, nil // mutual like
 					},
@@ -87,6 +228,15 @@ func TestPutDecision(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "super like without like",
+			req: &pb.PutDecisionRequest{
+				ActorUserId:     "user1",
+				RecipientUserId: "user2",
+				SuperLike:       true,
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing recipient ID",
 			req: &pb.PutDecisionRequest{
@@ -108,7 +258,7 @@ func TestPutDecision(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var queries db.Querier
+			var queries db.Store
 			if tt.mock != nil {
 				queries = tt.mock()
 			}
@@ -133,7 +283,7 @@ func TestListLikedYou(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *pb.ListLikedYouRequest
-		mock    func() db.Querier
+		mock    func() db.Store
 		want    *pb.ListLikedYouResponse
 		wantErr bool
 	}{
@@ -142,7 +292,7 @@ func TestListLikedYou(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					listLikers: func(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error) {
 						return []db.ListLikersRow{
@@ -173,7 +323,7 @@ func TestListLikedYou(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var queries db.Querier
+			var queries db.Store
 			if tt.mock != nil {
 				queries = tt.mock()
 			}
@@ -199,7 +349,7 @@ func TestListNewLikedYou(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *pb.ListLikedYouRequest
-		mock    func() db.Querier
+		mock    func() db.Store
 		want    *pb.ListLikedYouResponse
 		wantErr bool
 	}{
@@ -208,7 +358,7 @@ func TestListNewLikedYou(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
 						return []db.ListNewLikersRow{
@@ -235,7 +385,7 @@ func TestListNewLikedYou(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
 						return []db.ListNewLikersRow{}, nil
@@ -256,7 +406,7 @@ func TestListNewLikedYou(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var queries db.Querier
+			var queries db.Store
 			if tt.mock != nil {
 				queries = tt.mock()
 			}
@@ -279,7 +429,7 @@ func TestCountLikedYou(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *pb.CountLikedYouRequest
-		mock    func() db.Querier
+		mock    func() db.Store
 		want    *pb.CountLikedYouResponse
 		wantErr bool
 	}{
@@ -288,7 +438,7 @@ func TestCountLikedYou(t *testing.T) {
 			req: &pb.CountLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					countLikers: func(ctx context.Context, recipientUserID string) (int64, error) {
 						return 5, nil // Mock 5 likes
@@ -304,7 +454,7 @@ func TestCountLikedYou(t *testing.T) {
 			req: &pb.CountLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					countLikers: func(ctx context.Context, recipientUserID string) (int64, error) {
 						return 0, nil
@@ -324,7 +474,7 @@ func TestCountLikedYou(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var queries db.Querier
+			var queries db.Store
 			if tt.mock != nil {
 				queries = tt.mock()
 			}
@@ -350,7 +500,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 	tests := []struct {
 		name    string
 		req     *pb.ListLikedYouRequest
-		mock    func() db.Querier
+		mock    func() db.Store
 		want    *pb.ListLikedYouResponse
 		wantErr bool
 	}{
@@ -359,7 +509,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
 						// Should only return user3, as user1 has mutual like
@@ -371,6 +521,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 						}, nil
 					},
 					// Add PutDecision to set up the test scenario
+					getDecision: noPreviousDecision,
 					putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
 						// Simulating user1 and user2 liking each other
 						if arg.ActorUserID == "user1" && arg.RecipientUserID == "user2" {
@@ -395,7 +546,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				// Simulate a sequence of events:
 				// 1. Initially user1 likes user2 (shows in new likes)
 				// 2. user2 likes user1 back (should no longer show in new likes)
@@ -404,6 +555,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 						// After mutual like is established, should return empty
 						return []db.ListNewLikersRow{}, nil
 					},
+					getDecision: noPreviousDecision,
 					putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
 						// When user2 likes user1 back
 						if arg.ActorUserID == "user2" && arg.RecipientUserID == "user1" {
@@ -423,7 +575,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				earlier := now.Add(-1 * time.Hour)
 				return mockQueries{
 					listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
@@ -460,7 +612,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 			req: &pb.ListLikedYouRequest{
 				RecipientUserId: "user2",
 			},
-			mock: func() db.Querier {
+			mock: func() db.Store {
 				return mockQueries{
 					listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
 						// User3 liked user2 after initially passing
@@ -471,6 +623,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 							},
 						}, nil
 					},
+					getDecision: noPreviousDecision,
 					putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
 						// Simulating user3 first passing, then liking
 						if arg.ActorUserID == "user3" && arg.RecipientUserID == "user2" {
@@ -494,7 +647,7 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var queries db.Querier
+			var queries db.Store
 			if tt.mock != nil {
 				queries = tt.mock()
 			}
@@ -512,3 +665,607 @@ func TestListNewLikedYou_MutualLikes(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorization(t *testing.T) {
+	queries := mockQueries{
+		getDecision: noPreviousDecision,
+		putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
+			return false, nil
+		},
+		countLikers: func(ctx context.Context, recipientUserID string) (int64, error) {
+			return 1, nil
+		},
+	}
+	s := NewExploreService(queries, WithAuthorization())
+
+	asUser := func(subject string, roles ...string) context.Context {
+		return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: subject, Roles: roles})
+	}
+	decision := &pb.PutDecisionRequest{
+		ActorUserId:     "user1",
+		RecipientUserId: "user2",
+		LikedRecipient:  true,
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		call     func(ctx context.Context) error
+		wantCode codes.Code
+	}{
+		{
+			name: "actor decides for themselves",
+			ctx:  asUser("user1"),
+			call: func(ctx context.Context) error {
+				_, err := s.PutDecision(ctx, decision)
+				return err
+			},
+		},
+		{
+			name: "actor impersonates another user",
+			ctx:  asUser("user3"),
+			call: func(ctx context.Context) error {
+				_, err := s.PutDecision(ctx, decision)
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "internal service acts for any user",
+			ctx:  asUser("matcher", auth.RoleInternal),
+			call: func(ctx context.Context) error {
+				_, err := s.PutDecision(ctx, decision)
+				return err
+			},
+		},
+		{
+			name: "recipient counts own likes",
+			ctx:  asUser("user2"),
+			call: func(ctx context.Context) error {
+				_, err := s.CountLikedYou(ctx, &pb.CountLikedYouRequest{RecipientUserId: "user2"})
+				return err
+			},
+		},
+		{
+			name: "another user lists recipient's likes",
+			ctx:  asUser("user1"),
+			call: func(ctx context.Context) error {
+				_, err := s.ListLikedYou(ctx, &pb.ListLikedYouRequest{RecipientUserId: "user2"})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "no principal",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := s.CountLikedYou(ctx, &pb.CountLikedYouRequest{RecipientUserId: "user2"})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(tt.ctx)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestPutDecision_Quota(t *testing.T) {
+	oldest := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		req          *pb.PutDecisionRequest
+		alreadyLiked bool
+		counted      bool
+		used         int64
+		wantCode     codes.Code
+		wantRecorded bool
+	}{
+		{
+			name:         "like within quota",
+			req:          &pb.PutDecisionRequest{ActorUserId: "user1", RecipientUserId: "user2", LikedRecipient: true},
+			used:         2,
+			wantRecorded: true,
+		},
+		{
+			name:     "like over quota",
+			req:      &pb.PutDecisionRequest{ActorUserId: "user1", RecipientUserId: "user2", LikedRecipient: true},
+			used:     3,
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:         "re-like over quota is free",
+			req:          &pb.PutDecisionRequest{ActorUserId: "user1", RecipientUserId: "user2", LikedRecipient: true},
+			alreadyLiked: true,
+			used:         3,
+		},
+		{
+			name:    "like again after a pass over quota is free",
+			req:     &pb.PutDecisionRequest{ActorUserId: "user1", RecipientUserId: "user2", LikedRecipient: true},
+			counted: true,
+			used:    3,
+		},
+		{
+			name: "pass over quota is free",
+			req:  &pb.PutDecisionRequest{ActorUserId: "user1", RecipientUserId: "user2", LikedRecipient: false},
+			used: 3,
+		},
+		{
+			name: "premium user over quota",
+			req:  &pb.PutDecisionRequest{ActorUserId: "premium1", RecipientUserId: "user2", LikedRecipient: true},
+			used: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorded := false
+			queries := mockQueries{
+				putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
+					return false, nil
+				},
+				getDecision: func(ctx context.Context, arg db.GetDecisionParams) (bool, error) {
+					if !tt.alreadyLiked {
+						return false, pgx.ErrNoRows
+					}
+					return true, nil
+				},
+				getQuotaUsage: func(ctx context.Context, arg db.GetQuotaUsageParams) (db.GetQuotaUsageRow, error) {
+					return db.GetQuotaUsageRow{Used: tt.used, Oldest: oldest}, nil
+				},
+				isQuotaLikeCounted: func(ctx context.Context, arg db.IsQuotaLikeCountedParams) (bool, error) {
+					return tt.counted, nil
+				},
+				recordQuotaLike: func(ctx context.Context, arg db.RecordQuotaLikeParams) error {
+					recorded = true
+					return nil
+				},
+			}
+			q := quota.NewManager(queries, entitlements.NewStatic([]string{"premium1"}), 3, 24*time.Hour)
+			s := NewExploreService(queries, WithQuota(q))
+
+			_, err := s.PutDecision(context.Background(), tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantRecorded, recorded)
+		})
+	}
+}
+
+func TestGetQuota(t *testing.T) {
+	oldest := time.Now().Add(-time.Hour).Truncate(time.Second)
+	queries := mockQueries{
+		getQuotaUsage: func(ctx context.Context, arg db.GetQuotaUsageParams) (db.GetQuotaUsageRow, error) {
+			return db.GetQuotaUsageRow{Used: 2, Oldest: oldest}, nil
+		},
+	}
+	q := quota.NewManager(queries, entitlements.NewStatic([]string{"premium1"}), 5, 24*time.Hour)
+	s := NewExploreService(queries, WithQuota(q))
+
+	got, err := s.GetQuota(context.Background(), &pb.GetQuotaRequest{UserId: "user1"})
+	require.NoError(t, err)
+	assert.Equal(t, &pb.GetQuotaResponse{
+		Limit:              5,
+		Remaining:          3,
+		ResetUnixTimestamp: uint64(oldest.Add(24 * time.Hour).Unix()),
+	}, got)
+
+	got, err = s.GetQuota(context.Background(), &pb.GetQuotaRequest{UserId: "premium1"})
+	require.NoError(t, err)
+	assert.Equal(t, &pb.GetQuotaResponse{Unlimited: true}, got)
+
+	_, err = s.GetQuota(context.Background(), &pb.GetQuotaRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Without a quota every user is unlimited
+	got, err = NewExploreService(queries).GetQuota(context.Background(), &pb.GetQuotaRequest{UserId: "user1"})
+	require.NoError(t, err)
+	assert.True(t, got.Unlimited)
+}
+
+func TestListLikedYou_Visibility(t *testing.T) {
+	now := time.Now()
+	emptyString := ""
+	policy := visibility.NewPolicy(entitlements.NewStatic([]string{"premium1"}), []byte("key"))
+
+	queries := mockQueries{
+		listLikers: func(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error) {
+			return []db.ListLikersRow{{ActorUserID: "user1", CreatedAt: now}}, nil
+		},
+		listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
+			return []db.ListNewLikersRow{{ActorUserID: "user1", CreatedAt: now}}, nil
+		},
+		// Totals apply the same filters as the list
+		countFilteredLikers: func(ctx context.Context, arg db.CountFilteredLikersParams) (int64, error) {
+			assert.Equal(t, db.CountFilteredLikersParams{
+				RecipientUserID: "user2",
+				IncludeSuper:    true,
+				Since:           time.Unix(100, 0),
+				Until:           time.Unix(200, 0),
+			}, arg)
+			return 7, nil
+		},
+		countNewLikers: func(ctx context.Context, arg db.CountNewLikersParams) (int64, error) {
+			assert.Equal(t, db.CountNewLikersParams{
+				RecipientUserID: "user2",
+				IncludeSuper:    true,
+				Since:           time.Unix(100, 0),
+				Until:           time.Unix(200, 0),
+			}, arg)
+			return 4, nil
+		},
+	}
+	s := NewExploreService(queries, WithVisibilityPolicy(policy))
+	since, until := uint64(100), uint64(200)
+
+	tests := []struct {
+		name      string
+		recipient string
+		list      func(ctx context.Context, req *pb.ListLikedYouRequest) (*pb.ListLikedYouResponse, error)
+		want      *pb.ListLikedYouResponse
+	}{
+		{
+			name:      "free user gets teaser",
+			recipient: "user2",
+			list:      s.ListLikedYou,
+			want: &pb.ListLikedYouResponse{
+				Likers: []*pb.ListLikedYouResponse_Liker{
+					{
+						Handle:        policy.Handle("user2", "user1"),
+						UnixTimestamp: uint64(now.Unix()),
+					},
+				},
+				NextPaginationToken: &emptyString,
+				Redacted:            true,
+				TotalCount:          7,
+			},
+		},
+		{
+			name:      "free user gets teaser of new likes",
+			recipient: "user2",
+			list:      s.ListNewLikedYou,
+			want: &pb.ListLikedYouResponse{
+				Likers: []*pb.ListLikedYouResponse_Liker{
+					{
+						Handle:        policy.Handle("user2", "user1"),
+						UnixTimestamp: uint64(now.Unix()),
+					},
+				},
+				NextPaginationToken: &emptyString,
+				Redacted:            true,
+				TotalCount:          4,
+			},
+		},
+		{
+			name:      "premium user sees likers",
+			recipient: "premium1",
+			list:      s.ListLikedYou,
+			want: &pb.ListLikedYouResponse{
+				Likers: []*pb.ListLikedYouResponse_Liker{
+					{
+						ActorId:       "user1",
+						UnixTimestamp: uint64(now.Unix()),
+					},
+				},
+				NextPaginationToken: &emptyString,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.list(context.Background(), &pb.ListLikedYouRequest{
+				RecipientUserId:    tt.recipient,
+				LikeType:           pb.LikeType_LIKE_TYPE_SUPER,
+				SinceUnixTimestamp: &since,
+				UntilUnixTimestamp: &until,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetExploreDeck(t *testing.T) {
+	source := deck.NewStaticSource(map[string][]string{
+		deck.DefaultPool: {"user2", "user3", "user1", "user4", "user2", "user5"},
+	})
+	queries := mockQueries{
+		listDecidedRecipients: func(ctx context.Context, arg db.ListDecidedRecipientsParams) ([]string, error) {
+			assert.Equal(t, "user1", arg.ActorUserID)
+			assert.Equal(t, []string{"user2", "user3", "user4", "user5"}, arg.CandidateUserIds)
+			return []string{"user3"}, nil
+		},
+		listLikersAmong: func(ctx context.Context, arg db.ListLikersAmongParams) ([]string, error) {
+			assert.Equal(t, "user1", arg.RecipientUserID)
+			return []string{"user4"}, nil
+		},
+	}
+	s := NewExploreService(queries, WithCandidateSource(source))
+
+	resp, err := s.GetExploreDeck(context.Background(), &pb.GetExploreDeckRequest{ActorUserId: "user1", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.GetExploreDeckResponse_Candidate{{UserId: "user4"}, {UserId: "user2"}}, resp.Candidates)
+	require.NotEmpty(t, resp.GetNextPaginationToken())
+
+	resp, err = s.GetExploreDeck(context.Background(), &pb.GetExploreDeckRequest{
+		ActorUserId:     "user1",
+		Limit:           2,
+		PaginationToken: resp.NextPaginationToken,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.GetExploreDeckResponse_Candidate{{UserId: "user5"}}, resp.Candidates)
+	assert.Empty(t, resp.GetNextPaginationToken())
+
+	invalid := "invalid"
+	_, err = s.GetExploreDeck(context.Background(), &pb.GetExploreDeckRequest{ActorUserId: "user1", PaginationToken: &invalid})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.GetExploreDeck(context.Background(), &pb.GetExploreDeckRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = NewExploreService(queries).GetExploreDeck(context.Background(), &pb.GetExploreDeckRequest{ActorUserId: "user1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestUserScore(t *testing.T) {
+	var previous *bool
+	stored := map[string]db.UserScore{
+		"user1": {UserID: "user1", Score: 1200, LikesReceived: 3, PassesReceived: 1, UpdatedAt: time.Unix(1700000000, 0)},
+	}
+	var writes int
+	queries := mockQueries{
+		putDecision: func(ctx context.Context, arg db.PutDecisionParams) (bool, error) {
+			previous = &arg.Liked
+			return false, nil
+		},
+		getDecision: func(ctx context.Context, arg db.GetDecisionParams) (bool, error) {
+			if previous == nil {
+				return false, pgx.ErrNoRows
+			}
+			return *previous, nil
+		},
+		getUserScore: func(ctx context.Context, userID string) (db.UserScore, error) {
+			if score, ok := stored[userID]; ok {
+				return score, nil
+			}
+			return db.UserScore{}, pgx.ErrNoRows
+		},
+		lockUserScore: func(ctx context.Context, arg db.LockUserScoreParams) (db.UserScore, error) {
+			if score, ok := stored[arg.UserID]; ok {
+				return score, nil
+			}
+			return db.UserScore{UserID: arg.UserID, Score: arg.InitialScore}, nil
+		},
+		setUserScore: func(ctx context.Context, arg db.SetUserScoreParams) error {
+			writes++
+			stored[arg.UserID] = db.UserScore{UserID: arg.UserID, Score: arg.Score, LikesReceived: arg.LikesReceived, PassesReceived: arg.PassesReceived}
+			return nil
+		},
+	}
+	s := NewExploreService(queries, WithScorer(scoring.NewScorer(queries)), WithAuthorization())
+	internal := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "ranking", Roles: []string{auth.RoleInternal}})
+	decide := func(liked bool) {
+		_, err := s.PutDecision(auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user1"}), &pb.PutDecisionRequest{
+			ActorUserId:     "user1",
+			RecipientUserId: "user2",
+			LikedRecipient:  liked,
+		})
+		require.NoError(t, err)
+	}
+
+	// Decisions update the recipient's score
+	decide(true)
+	liked := scoring.InitialScore + scoring.Delta(scoring.InitialScore, 1200, true)
+	assert.Equal(t, db.UserScore{UserID: "user2", Score: liked, LikesReceived: 1}, stored["user2"])
+
+	// Repeating a decision changes nothing
+	decide(true)
+	assert.Equal(t, 1, writes)
+
+	// Changing a decision replaces its contribution
+	decide(false)
+	assert.Equal(t, int64(0), stored["user2"].LikesReceived)
+	assert.Equal(t, int64(1), stored["user2"].PassesReceived)
+	assert.Less(t, stored["user2"].Score, scoring.InitialScore)
+
+	resp, err := s.GetUserScore(internal, &pb.GetUserScoreRequest{UserId: "user1"})
+	require.NoError(t, err)
+	assert.Equal(t, &pb.GetUserScoreResponse{
+		Score:                1200,
+		LikesReceived:        3,
+		PassesReceived:       1,
+		UpdatedUnixTimestamp: 1700000000,
+	}, resp)
+
+	// Users without decisions have the initial score
+	resp, err = s.GetUserScore(internal, &pb.GetUserScoreRequest{UserId: "user3"})
+	require.NoError(t, err)
+	assert.Equal(t, &pb.GetUserScoreResponse{Score: scoring.InitialScore}, resp)
+
+	// Only internal callers may read scores
+	_, err = s.GetUserScore(auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "user1"}), &pb.GetUserScoreRequest{UserId: "user1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestListLikedYou_SortAndFilter(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	likers := make([]db.ListLikersOldestFirstRow, pageSize+1)
+	for i := range likers {
+		likers[i] = db.ListLikersOldestFirstRow{
+			ActorUserID: "user" + string(rune('a'+i%26)),
+			CreatedAt:   base, // Likes in the same second must still page correctly
+			ID:          int64(i + 1),
+			SuperLike:   true,
+		}
+	}
+
+	var got db.ListLikersOldestFirstParams
+	s := NewExploreService(mockQueries{
+		listLikersOldestFirst: func(ctx context.Context, arg db.ListLikersOldestFirstParams) ([]db.ListLikersOldestFirstRow, error) {
+			got = arg
+			return likers, nil
+		},
+		listLikers: func(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error) {
+			t.Fatal("newest first query used for oldest first request")
+			return nil, nil
+		},
+	})
+
+	since, until := uint64(base.Add(-time.Hour).Unix()), uint64(base.Add(time.Hour).Unix())
+	req := &pb.ListLikedYouRequest{
+		RecipientUserId:    "user2",
+		SortOrder:          pb.SortOrder_SORT_ORDER_OLDEST_FIRST,
+		SinceUnixTimestamp: &since,
+		UntilUnixTimestamp: &until,
+		LikeType:           pb.LikeType_LIKE_TYPE_SUPER,
+	}
+	resp, err := s.ListLikedYou(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Likers, pageSize)
+	assert.True(t, resp.Likers[0].SuperLike)
+	assert.False(t, got.IncludeRegular)
+	assert.True(t, got.IncludeSuper)
+	assert.Equal(t, base.Add(-time.Hour), got.Since)
+	assert.Equal(t, base.Add(time.Hour), got.Until)
+	assert.True(t, got.CreatedAtCursor.IsZero())
+
+	// The next page continues after the last decision, even within the same second
+	req.PaginationToken = resp.NextPaginationToken
+	_, err = s.ListLikedYou(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, base.Equal(got.CreatedAtCursor))
+	assert.Equal(t, int64(pageSize), got.IDCursor)
+
+	// Tokens can't be reused with a different sort order
+	req.SortOrder = pb.SortOrder_SORT_ORDER_NEWEST_FIRST
+	_, err = s.ListLikedYou(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The time range can't be empty
+	_, err = s.ListLikedYou(context.Background(), &pb.ListLikedYouRequest{
+		RecipientUserId:    "user2",
+		SinceUnixTimestamp: &until,
+		UntilUnixTimestamp: &since,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDecodePaginationToken_Legacy(t *testing.T) {
+	s := NewExploreService(nil)
+
+	// Tokens issued before sort orders hold a bare Unix timestamp
+	token := base64.StdEncoding.EncodeToString([]byte("1700000000"))
+	cursor, err := s.decodePaginationToken(&token, false)
+	require.NoError(t, err)
+	assert.True(t, time.Unix(1700000000, 0).Equal(cursor.createdAt()))
+	assert.Zero(t, cursor.ID)
+
+	invalid := "not a token"
+	_, err = s.decodePaginationToken(&invalid, false)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLikesSeen(t *testing.T) {
+	stored := time.Unix(1700000500, 0)
+	var marked db.MarkLikesSeenParams
+	s := NewExploreService(mockQueries{
+		markLikesSeen: func(ctx context.Context, arg db.MarkLikesSeenParams) (time.Time, error) {
+			marked = arg
+			return stored, nil // An earlier call already moved the watermark further
+		},
+		countUnseenLikers: func(ctx context.Context, arg db.CountUnseenLikersParams) (int64, error) {
+			return 3, nil
+		},
+		listLikers: func(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error) {
+			return []db.ListLikersRow{
+				{ActorUserID: "user3", CreatedAt: stored.Add(time.Minute)},
+				{ActorUserID: "user1", CreatedAt: stored.Add(-time.Minute), Seen: true},
+			}, nil
+		},
+	})
+
+	seenUntil := uint64(1700000000)
+	resp, err := s.MarkLikesSeen(context.Background(), &pb.MarkLikesSeenRequest{
+		RecipientUserId:        "user2",
+		SeenUntilUnixTimestamp: &seenUntil,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "user2", marked.RecipientUserID)
+	assert.Equal(t, time.Unix(1700000001, 0), marked.SeenBefore, "likes within the given second are seen")
+	assert.Equal(t, uint64(stored.Unix()), resp.SeenBeforeUnixTimestamp)
+
+	// Without a timestamp everything liked so far is seen
+	_, err = s.MarkLikesSeen(context.Background(), &pb.MarkLikesSeenRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), marked.SeenBefore, time.Minute)
+
+	count, err := s.CountUnseenLikedYou(context.Background(), &pb.CountLikedYouRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count.Count)
+
+	list, err := s.ListLikedYou(context.Background(), &pb.ListLikedYouRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+	require.Len(t, list.Likers, 2)
+	assert.False(t, list.Likers[0].Seen)
+	assert.True(t, list.Likers[1].Seen)
+
+	_, err = s.MarkLikesSeen(context.Background(), &pb.MarkLikesSeenRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	for _, future := range []uint64{uint64(time.Now().Add(time.Hour).Unix()), math.MaxUint64} {
+		_, err = s.MarkLikesSeen(context.Background(), &pb.MarkLikesSeenRequest{
+			RecipientUserId:        "user2",
+			SeenUntilUnixTimestamp: &future,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), future)
+	}
+	_, err = s.CountUnseenLikedYou(context.Background(), &pb.CountLikedYouRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLikeExpiry(t *testing.T) {
+	var listed db.ListNewLikersParams
+	var counted db.CountUnseenLikersParams
+	queries := mockQueries{
+		listNewLikers: func(ctx context.Context, arg db.ListNewLikersParams) ([]db.ListNewLikersRow, error) {
+			listed = arg
+			return nil, nil
+		},
+		listLikers: func(ctx context.Context, arg db.ListLikersParams) ([]db.ListLikersRow, error) {
+			assert.True(t, arg.Since.IsZero(), "expiry doesn't apply to the full like history")
+			return nil, nil
+		},
+		countUnseenLikers: func(ctx context.Context, arg db.CountUnseenLikersParams) (int64, error) {
+			counted = arg
+			return 0, nil
+		},
+	}
+	s := NewExploreService(queries, WithLikeExpiry(30*24*time.Hour))
+	expiredBefore := time.Now().Add(-30 * 24 * time.Hour)
+
+	_, err := s.ListNewLikedYou(context.Background(), &pb.ListLikedYouRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, expiredBefore, listed.Since, time.Minute)
+
+	// A later lower bound from the request wins
+	since := uint64(time.Now().Add(-time.Hour).Unix())
+	_, err = s.ListNewLikedYou(context.Background(), &pb.ListLikedYouRequest{RecipientUserId: "user2", SinceUnixTimestamp: &since})
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(int64(since), 0), listed.Since)
+
+	_, err = s.ListLikedYou(context.Background(), &pb.ListLikedYouRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+
+	_, err = s.CountUnseenLikedYou(context.Background(), &pb.CountLikedYouRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, expiredBefore, counted.ExpiredBefore, time.Minute)
+
+	// Without expiry every like counts
+	_, err = NewExploreService(queries).CountUnseenLikedYou(context.Background(), &pb.CountLikedYouRequest{RecipientUserId: "user2"})
+	require.NoError(t, err)
+	assert.True(t, counted.ExpiredBefore.IsZero())
+}
@@ -0,0 +1,174 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Supported values for Config.ClientAuth
+const (
+	ClientAuthRequire  = "require"
+	ClientAuthOptional = "optional"
+)
+
+// nextProtos are offered during ALPN. The configs handed out per connection
+// replace the server's own, so they must list h2 themselves for HTTP/2 and
+// gRPC to be negotiated over TLS
+var nextProtos = []string{"h2", "http/1.1"}
+
+// reloadCheckInterval bounds how often certificate files are checked for changes
+const reloadCheckInterval = 5 * time.Second
+
+// Config describes the server certificate and, for mutual TLS, the CA bundle
+// used to verify client certificates
+type Config struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // Enables client certificate verification when set
+	ClientAuth   string // require (default) or optional
+}
+
+// New builds a server TLS config. Certificate, key and CA files are re-read
+// when their modification time changes, so rotated certificates are picked
+// up by new connections without restarting the service.
+func New(cfg Config, logger *slog.Logger) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("both a TLS certificate and key are required")
+	}
+
+	clientAuth := tls.NoClientCert
+	if cfg.ClientCAFile != "" {
+		switch cfg.ClientAuth {
+		case "", ClientAuthRequire:
+			clientAuth = tls.RequireAndVerifyClientCert
+		case ClientAuthOptional:
+			clientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("unknown client auth mode %q", cfg.ClientAuth)
+		}
+	}
+
+	r := &reloader{
+		cfg:        cfg,
+		clientAuth: clientAuth,
+		logger:     logger,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		NextProtos:         nextProtos,
+		GetConfigForClient: r.configForClient,
+	}, nil
+}
+
+// reloader caches the parsed certificates and reloads them when the files change
+type reloader struct {
+	cfg        Config
+	clientAuth tls.ClientAuthType
+	logger     *slog.Logger
+
+	mu          sync.Mutex
+	config      *tls.Config
+	modTimes    map[string]time.Time
+	lastChecked time.Time
+}
+
+// configForClient returns the current config, reloading it first if any file changed
+func (r *reloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastChecked) >= reloadCheckInterval {
+		r.lastChecked = time.Now()
+		if r.changed() {
+			if err := r.loadLocked(); err != nil {
+				// Keep serving the previous certificate until the files are fixed
+				r.logger.Error("failed to reload TLS certificates", "error", err)
+			} else {
+				r.logger.Info("reloaded TLS certificates")
+			}
+		}
+	}
+
+	return r.config, nil
+}
+
+func (r *reloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastChecked = time.Now()
+	return r.loadLocked()
+}
+
+func (r *reloader) loadLocked() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   r.clientAuth,
+		NextProtos:   nextProtos,
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("client CA bundle contains no certificates")
+		}
+		config.ClientCAs = pool
+	}
+
+	r.config = config
+	r.modTimes = modTimes
+	return nil
+}
+
+// changed reports whether any file's modification time differs from the loaded version
+func (r *reloader) changed() bool {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return true
+	}
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reloader) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
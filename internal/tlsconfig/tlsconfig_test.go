@@ -0,0 +1,175 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSigned writes a self-signed certificate and key for commonName to dir
+func writeSelfSigned(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func leafCommonName(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSigned(t, dir, "explore")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	config, err := New(Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile}, logger)
+	require.NoError(t, err)
+
+	current, err := config.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, current.ClientAuth)
+	assert.NotNil(t, current.ClientCAs)
+
+	config, err = New(Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, ClientAuth: ClientAuthOptional}, logger)
+	require.NoError(t, err)
+	current, err = config.GetConfigForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, current.ClientAuth)
+
+	_, err = New(Config{CertFile: certFile}, logger)
+	assert.Error(t, err)
+
+	_, err = New(Config{CertFile: certFile, KeyFile: filepath.Join(dir, "missing.key")}, logger)
+	assert.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSigned(t, dir, "before")
+
+	r := &reloader{
+		cfg:    Config{CertFile: certFile, KeyFile: keyFile},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	require.NoError(t, r.load())
+
+	config, err := r.configForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "before", leafCommonName(t, config))
+
+	// Rotate the certificate and make sure the change is visible to the next check
+	writeSelfSigned(t, dir, "after")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	r.lastChecked = time.Time{}
+
+	config, err = r.configForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "after", leafCommonName(t, config))
+
+	// A broken rotation keeps serving the last good certificate
+	require.NoError(t, os.WriteFile(certFile, []byte("not a certificate"), 0o600))
+	future = future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	r.lastChecked = time.Time{}
+
+	config, err = r.configForClient(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "after", leafCommonName(t, config))
+}
+
+func TestNew_NegotiatesHTTP2(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSigned(t, dir, "explore")
+
+	config, err := New(Config{CertFile: certFile, KeyFile: keyFile}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &http.Server{
+		Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, r.Proto) }),
+		TLSConfig:         config,
+		ReadHeaderTimeout: time.Second,
+	}
+	go server.ServeTLS(listener, "", "")
+	t.Cleanup(func() { server.Close() })
+
+	certPEM, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+
+	tests := []struct {
+		name      string
+		http2     bool
+		wantProto string
+	}{
+		{name: "h2", http2: true, wantProto: "HTTP/2.0"},
+		{name: "http/1.1", http2: false, wantProto: "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &http.Transport{
+				TLSClientConfig:   &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12},
+				ForceAttemptHTTP2: tt.http2,
+			}
+			t.Cleanup(transport.CloseIdleConnections)
+
+			resp, err := (&http.Client{Transport: transport}).Get("https://" + listener.Addr().String())
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantProto, string(body))
+			if tt.http2 {
+				assert.Equal(t, "h2", resp.TLS.NegotiatedProtocol)
+			}
+		})
+	}
+}
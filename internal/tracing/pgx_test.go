@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "sqlc query",
+			sql:  "-- name: ListNewLikers :many\nSELECT d1.actor_user_id FROM decisions d1",
+			want: "ListNewLikers",
+		},
+		{
+			name: "leading whitespace",
+			sql:  "\n  -- name: CountLikers :one\nSELECT COUNT(*) FROM decisions",
+			want: "CountLikers",
+		},
+		{
+			name: "plain query",
+			sql:  "select 1",
+			want: "SELECT",
+		},
+		{
+			name: "empty query",
+			sql:  "",
+			want: "query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, QueryName(tt.sql))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package verify
+
+// Check finds one kind of anomaly and repairs it
+type Check struct {
+	Name        string
+	Description string
+	// find selects a row per anomaly, with the columns identifying it, and
+	// locks the rows the repair changes
+	find string
+	// repair fixes every anomaly find selects
+	repair string
+	// rescore recomputes every score after repairing, as each user's score
+	// depends on the scores of everyone who decided about them
+	rescore bool
+}
+
+// actualCounts counts the likes and passes each user has received
+const actualCounts = `
+SELECT recipient_user_id,
+       COUNT(*) FILTER (WHERE liked) AS likes,
+       COUNT(*) FILTER (WHERE NOT liked) AS passes
+FROM decisions
+GROUP BY recipient_user_id`
+
+// Checks lists every check, in the order they run. Each check runs in its own
+// transaction, repairing before the next check starts, so decisions are fixed
+// before the rows derived from them.
+var Checks = []Check{
+	{
+		Name:        "self_decisions",
+		Description: "Decisions users made about themselves",
+		find: `
+SELECT actor_user_id, liked, created_at
+FROM decisions
+WHERE actor_user_id = recipient_user_id
+FOR UPDATE`,
+		repair: `
+DELETE FROM decisions
+WHERE actor_user_id = recipient_user_id`,
+	},
+	{
+		Name:        "super_like_without_like",
+		Description: "Passes flagged as super-likes",
+		find: `
+SELECT actor_user_id, recipient_user_id, updated_at
+FROM decisions
+WHERE super_like AND NOT liked
+FOR UPDATE`,
+		repair: `
+UPDATE decisions
+SET super_like = false
+WHERE super_like AND NOT liked`,
+	},
+	{
+		Name:        "updated_before_created",
+		Description: "Decisions last updated before they were created",
+		find: `
+SELECT actor_user_id, recipient_user_id, created_at, updated_at
+FROM decisions
+WHERE updated_at < created_at
+FOR UPDATE`,
+		repair: `
+UPDATE decisions
+SET updated_at = created_at
+WHERE updated_at < created_at`,
+	},
+	{
+		// There are no rows to lock, so the score table lock taken before
+		// repairing keeps decisions from adding them. The initial score is
+		// replaced by the recompute.
+		Name:        "missing_scores",
+		Description: "Users who have received decisions but have no user score",
+		find: `
+SELECT c.recipient_user_id AS user_id, c.likes, c.passes
+FROM (` + actualCounts + `) c
+WHERE NOT EXISTS (SELECT 1 FROM user_scores s WHERE s.user_id = c.recipient_user_id)`,
+		repair: `
+INSERT INTO user_scores (user_id, score, likes_received, passes_received)
+SELECT c.recipient_user_id, 1000, c.likes, c.passes
+FROM (` + actualCounts + `) c
+WHERE NOT EXISTS (SELECT 1 FROM user_scores s WHERE s.user_id = c.recipient_user_id)`,
+		rescore: true,
+	},
+	{
+		Name:        "score_counters",
+		Description: "User scores whose like and pass counters disagree with the decisions received",
+		find: `
+SELECT s.user_id, s.likes_received, s.passes_received, c.likes AS actual_likes, c.passes AS actual_passes
+FROM user_scores s
+         JOIN (` + actualCounts + `) c ON c.recipient_user_id = s.user_id
+WHERE (s.likes_received, s.passes_received) <> (c.likes, c.passes)
+FOR UPDATE OF s`,
+		repair: `
+UPDATE user_scores s
+SET likes_received = c.likes, passes_received = c.passes, updated_at = NOW()
+FROM (` + actualCounts + `) c
+WHERE c.recipient_user_id = s.user_id
+  AND (s.likes_received, s.passes_received) <> (c.likes, c.passes)`,
+		rescore: true,
+	},
+	{
+		Name:        "orphaned_scores",
+		Description: "User scores for users who haven't received any decisions",
+		find: `
+SELECT user_id, score
+FROM user_scores s
+WHERE NOT EXISTS (SELECT 1 FROM decisions d WHERE d.recipient_user_id = s.user_id)
+FOR UPDATE`,
+		repair: `
+DELETE FROM user_scores s
+WHERE NOT EXISTS (SELECT 1 FROM decisions d WHERE d.recipient_user_id = s.user_id)`,
+	},
+	{
+		Name:        "orphaned_expired_likes",
+		Description: "Archived expired likes without a decision between the users",
+		find: `
+SELECT actor_user_id, recipient_user_id, liked_at
+FROM expired_likes e
+WHERE NOT EXISTS (
+    SELECT 1 FROM decisions d
+    WHERE d.actor_user_id = e.actor_user_id AND d.recipient_user_id = e.recipient_user_id
+)
+FOR UPDATE`,
+		repair: `
+DELETE FROM expired_likes e
+WHERE NOT EXISTS (
+    SELECT 1 FROM decisions d
+    WHERE d.actor_user_id = e.actor_user_id AND d.recipient_user_id = e.recipient_user_id
+)`,
+	},
+	{
+		Name:        "orphaned_quota_likes",
+		Description: "Likes counted against a quota without a decision between the users",
+		find: `
+SELECT actor_user_id, recipient_user_id, created_at
+FROM quota_likes q
+WHERE NOT EXISTS (
+    SELECT 1 FROM decisions d
+    WHERE d.actor_user_id = q.actor_user_id AND d.recipient_user_id = q.recipient_user_id
+)
+FOR UPDATE`,
+		repair: `
+DELETE FROM quota_likes q
+WHERE NOT EXISTS (
+    SELECT 1 FROM decisions d
+    WHERE d.actor_user_id = q.actor_user_id AND d.recipient_user_id = q.recipient_user_id
+)`,
+	},
+}
@@ -0,0 +1,146 @@
+// Package verify scans the database for data that breaks the service's
+// invariants, such as rows written by old versions, manual fixes or imports
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"muzz-explore-service/internal/db"
+	"muzz-explore-service/internal/scoring"
+)
+
+// DefaultSamples is how many example rows are reported per check
+const DefaultSamples = 10
+
+// Result is the outcome of one check
+type Result struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Anomalies   int64             `json:"anomalies"`
+	Samples     []json.RawMessage `json:"samples"`  // Up to the sample limit, as JSON objects of the identifying columns
+	Repaired    int64             `json:"repaired"` // Rows changed by the repair
+}
+
+// Report is the machine-readable outcome of a verification run
+type Report struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Repair    bool      `json:"repair"`
+	Anomalies int64     `json:"anomalies"` // Total found across all checks
+	Repaired  int64     `json:"repaired"`  // Total rows changed by repairs
+	Checks    []Result  `json:"checks"`
+}
+
+// Clean reports whether no anomalies are left, either because none were found
+// or because every one was repaired
+func (r Report) Clean() bool {
+	return r.Anomalies == 0 || (r.Repair && r.Repaired >= r.Anomalies)
+}
+
+// lockScores stops decisions from updating scores until the transaction ends,
+// so recomputed scores can't overwrite them
+const lockScores = `LOCK TABLE user_scores IN EXCLUSIVE MODE`
+
+// Conn begins the transactions checks run in, such as a pgxpool.Pool
+type Conn interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Verifier runs checks against a database
+type Verifier struct {
+	conn    Conn
+	samples int
+}
+
+// NewVerifier creates a verifier reporting up to samples example rows per check
+func NewVerifier(conn Conn, samples int) *Verifier {
+	return &Verifier{conn: conn, samples: samples}
+}
+
+// Run runs every check, repairing the anomalies found if repair is set
+func (v *Verifier) Run(ctx context.Context, repair bool) (Report, error) {
+	report := Report{CheckedAt: time.Now().UTC(), Repair: repair}
+
+	for _, check := range Checks {
+		var result Result
+		err := pgx.BeginFunc(ctx, v.conn, func(tx pgx.Tx) error {
+			var err error
+			result, err = v.run(ctx, tx, check, repair)
+			return err
+		})
+		if err != nil {
+			return report, err
+		}
+
+		report.Anomalies += result.Anomalies
+		report.Repaired += result.Repaired
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report, nil
+}
+
+// run runs one check in tx. The find query locks the rows it selects, so they
+// can't change before they're repaired in the same transaction.
+func (v *Verifier) run(ctx context.Context, tx pgx.Tx, check Check, repair bool) (Result, error) {
+	result := Result{
+		Name:        check.Name,
+		Description: check.Description,
+		Samples:     []json.RawMessage{},
+	}
+
+	if repair && check.rescore {
+		if _, err := tx.Exec(ctx, lockScores); err != nil {
+			return result, fmt.Errorf("failed to lock scores for %s: %w", check.Name, err)
+		}
+	}
+
+	var samples []byte
+	query := fmt.Sprintf(`
+SELECT
+    (SELECT COUNT(*) FROM (%[1]s) f),
+    (SELECT COALESCE(jsonb_agg(to_jsonb(s)), '[]'::JSONB) FROM (%[1]s LIMIT $1) s)`, check.find)
+	if err := tx.QueryRow(ctx, query, v.samples).Scan(&result.Anomalies, &samples); err != nil {
+		return result, fmt.Errorf("failed to run check %s: %w", check.Name, err)
+	}
+	if err := json.Unmarshal(samples, &result.Samples); err != nil {
+		return result, fmt.Errorf("failed to decode samples of check %s: %w", check.Name, err)
+	}
+
+	if !repair || result.Anomalies == 0 {
+		return result, nil
+	}
+
+	tag, err := tx.Exec(ctx, check.repair)
+	if err != nil {
+		return result, fmt.Errorf("failed to repair %s: %w", check.Name, err)
+	}
+	result.Repaired = tag.RowsAffected()
+
+	if check.rescore {
+		if _, err := scoring.NewScorer(db.New(tx)).Recompute(ctx); err != nil {
+			return result, fmt.Errorf("failed to recompute scores for %s: %w", check.Name, err)
+		}
+	}
+	return result, nil
+}
+
+// WriteSummary prints a line per check for humans
+func (r Report) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tANOMALIES\tREPAIRED\tDESCRIPTION")
+	for _, c := range r.Checks {
+		repaired := "-"
+		if r.Repair {
+			repaired = fmt.Sprint(c.Repaired)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", c.Name, c.Anomalies, repaired, c.Description)
+	}
+	return tw.Flush()
+}
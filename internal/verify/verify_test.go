@@ -0,0 +1,174 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB answers every check with the anomalies configured for it, acting as
+// its own transaction
+type fakeDB struct {
+	pgx.Tx
+
+	anomalies  map[string]int64
+	err        error
+	repairs    []string
+	locks      int
+	recomputes int
+	commits    int
+}
+
+func (f *fakeDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return f, nil
+}
+
+func (f *fakeDB) Commit(ctx context.Context) error {
+	f.commits++
+	return nil
+}
+
+func (f *fakeDB) Rollback(ctx context.Context) error {
+	return nil
+}
+
+// Query answers the score recompute's listing of decisions with no rows
+func (f *fakeDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.recomputes++
+	return emptyRows{}, nil
+}
+
+type emptyRows struct {
+	pgx.Rows
+}
+
+func (emptyRows) Next() bool { return false }
+func (emptyRows) Close()     {}
+func (emptyRows) Err() error { return nil }
+
+type fakeRow struct {
+	count   int64
+	samples string
+	err     error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = r.count
+	*dest[1].(*[]byte) = []byte(r.samples)
+	return nil
+}
+
+// checkOf finds the check whose find query is part of sql
+func checkOf(sql string) Check {
+	for _, check := range Checks {
+		if strings.Contains(sql, check.find) || sql == check.repair {
+			return check
+		}
+	}
+	panic("unknown query: " + sql)
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	count := f.anomalies[checkOf(sql).Name]
+	samples := "[]"
+	if count > 0 {
+		samples = `[{"actor_user_id": "user1"}]`
+	}
+	return fakeRow{count: count, samples: samples, err: f.err}
+}
+
+func (f *fakeDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if sql == lockScores {
+		f.locks++
+		return pgconn.NewCommandTag("LOCK TABLE"), nil
+	}
+	check := checkOf(sql)
+	f.repairs = append(f.repairs, check.Name)
+	return pgconn.NewCommandTag("DELETE 2"), nil
+}
+
+func TestRun(t *testing.T) {
+	conn := &fakeDB{anomalies: map[string]int64{"self_decisions": 2, "score_counters": 3}}
+
+	report, err := NewVerifier(conn, DefaultSamples).Run(context.Background(), false)
+	require.NoError(t, err)
+	require.Len(t, report.Checks, len(Checks))
+	assert.Equal(t, int64(5), report.Anomalies)
+	assert.False(t, report.Clean())
+	assert.Empty(t, conn.repairs)
+	assert.Zero(t, conn.locks, "scores are only locked to repair them")
+	assert.Equal(t, len(Checks), conn.commits, "each check runs in its own transaction")
+
+	self := report.Checks[0]
+	assert.Equal(t, "self_decisions", self.Name)
+	assert.Equal(t, int64(2), self.Anomalies)
+	require.Len(t, self.Samples, 1)
+	assert.JSONEq(t, `{"actor_user_id": "user1"}`, string(self.Samples[0]))
+	assert.Empty(t, report.Checks[1].Samples)
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteSummary(&out))
+	assert.Regexp(t, `self_decisions\s+2\s+-\s+Decisions users made about themselves`, out.String())
+}
+
+func TestRunRepair(t *testing.T) {
+	conn := &fakeDB{anomalies: map[string]int64{"self_decisions": 2, "orphaned_scores": 1}}
+
+	report, err := NewVerifier(conn, DefaultSamples).Run(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"self_decisions", "orphaned_scores"}, conn.repairs, "only checks with anomalies are repaired")
+	assert.Equal(t, int64(3), report.Anomalies)
+	assert.Equal(t, int64(4), report.Repaired)
+	assert.True(t, report.Clean())
+
+	var out bytes.Buffer
+	require.NoError(t, report.WriteSummary(&out))
+	assert.Regexp(t, `self_decisions\s+2\s+2\s+`, out.String())
+	assert.Regexp(t, `updated_before_created\s+0\s+0\s+`, out.String())
+	assert.Zero(t, conn.recomputes, "scores are only recomputed after repairing counters")
+}
+
+func TestRunRepairScoreCounters(t *testing.T) {
+	conn := &fakeDB{anomalies: map[string]int64{"score_counters": 1}}
+
+	report, err := NewVerifier(conn, DefaultSamples).Run(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"score_counters"}, conn.repairs)
+	assert.Equal(t, 2, conn.locks, "both score checks lock scores before finding anomalies")
+	assert.Equal(t, 1, conn.recomputes)
+	assert.True(t, report.Clean())
+}
+
+func TestRunRepairMissingScores(t *testing.T) {
+	conn := &fakeDB{anomalies: map[string]int64{"missing_scores": 2}}
+
+	report, err := NewVerifier(conn, DefaultSamples).Run(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing_scores"}, conn.repairs)
+	assert.Equal(t, 1, conn.recomputes)
+	assert.True(t, report.Clean())
+}
+
+func TestRunError(t *testing.T) {
+	conn := &fakeDB{err: errors.New("connection refused")}
+
+	_, err := NewVerifier(conn, DefaultSamples).Run(context.Background(), false)
+	assert.EqualError(t, err, "failed to run check self_decisions: connection refused")
+}
+
+func TestClean(t *testing.T) {
+	assert.True(t, Report{}.Clean())
+	assert.False(t, Report{Anomalies: 1}.Clean())
+	assert.False(t, Report{Anomalies: 2, Repair: true, Repaired: 1}.Clean())
+	assert.True(t, Report{Anomalies: 2, Repair: true, Repaired: 2}.Clean())
+}
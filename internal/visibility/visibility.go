@@ -0,0 +1,54 @@
+package visibility
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/entitlements"
+)
+
+// Policy decides whether a recipient may see who liked them. Recipients
+// without the premium tier only get a teaser: how many people liked them,
+// with each liker replaced by an opaque handle.
+type Policy struct {
+	entitlements entitlements.Entitlements
+	key          []byte
+}
+
+// NewPolicy creates a policy backed by the given entitlements. The key signs
+// liker handles, so handles stay stable for as long as the key is unchanged.
+func NewPolicy(ents entitlements.Entitlements, key []byte) *Policy {
+	return &Policy{
+		entitlements: ents,
+		key:          key,
+	}
+}
+
+// CanSeeLikers reports whether the recipient may see the identities of their likers.
+// Internal services always see full data.
+func (p *Policy) CanSeeLikers(ctx context.Context, recipientUserID string) (bool, error) {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.HasRole(auth.RoleInternal) {
+		return true, nil
+	}
+
+	tier, err := p.entitlements.Tier(ctx, recipientUserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up tier: %w", err)
+	}
+	return tier == entitlements.TierPremium, nil
+}
+
+// Handle returns an opaque, stable identifier for a liker as seen by the
+// recipient. It cannot be reversed to the liker's ID, and differs between
+// recipients so handles can't be correlated across users.
+func (p *Policy) Handle(recipientUserID, actorUserID string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(recipientUserID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(actorUserID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,40 @@
+package visibility
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"muzz-explore-service/internal/auth"
+	"muzz-explore-service/internal/entitlements"
+)
+
+func TestCanSeeLikers(t *testing.T) {
+	p := NewPolicy(entitlements.NewStatic([]string{"premium1"}), []byte("key"))
+
+	canSee, err := p.CanSeeLikers(context.Background(), "premium1")
+	require.NoError(t, err)
+	assert.True(t, canSee)
+
+	canSee, err = p.CanSeeLikers(context.Background(), "user1")
+	require.NoError(t, err)
+	assert.False(t, canSee)
+
+	internal := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin", Roles: []string{auth.RoleInternal}})
+	canSee, err = p.CanSeeLikers(internal, "user1")
+	require.NoError(t, err)
+	assert.True(t, canSee)
+}
+
+func TestHandle(t *testing.T) {
+	p := NewPolicy(entitlements.NewStatic(nil), []byte("key"))
+
+	handle := p.Handle("user2", "user1")
+	assert.NotContains(t, handle, "user1")
+	assert.Equal(t, handle, p.Handle("user2", "user1"), "handles must be stable")
+	assert.NotEqual(t, handle, p.Handle("user2", "user3"), "likers must have distinct handles")
+	assert.NotEqual(t, handle, p.Handle("user4", "user1"), "handles must differ between recipients")
+	assert.NotEqual(t, handle, NewPolicy(entitlements.NewStatic(nil), []byte("other")).Handle("user2", "user1"))
+}
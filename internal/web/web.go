@@ -0,0 +1,64 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	connectcors "connectrpc.com/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// CORSConfig controls which browser origins may call the service
+type CORSConfig struct {
+	AllowedOrigins []string      // Exact origins, or "*" for any origin
+	MaxAge         time.Duration // How long browsers may cache preflight results
+}
+
+// NewHandler serves handler over HTTP/2 without TLS (h2c) as well as
+// HTTP/1.1, which Connect and gRPC-Web browser clients fall back to, behind CORS
+func NewHandler(handler http.Handler, cors CORSConfig) http.Handler {
+	return h2c.NewHandler(CORS(cors, handler), &http2.Server{})
+}
+
+// CORS answers preflight requests and adds CORS headers for allowed origins,
+// covering the headers used by the Connect, gRPC-Web and gRPC protocols
+func CORS(cfg CORSConfig, next http.Handler) http.Handler {
+	allowedMethods := strings.Join(connectcors.AllowedMethods(), ", ")
+	allowedHeaders := strings.Join(append(connectcors.AllowedHeaders(), "Authorization", "X-Request-Id"), ", ")
+	exposedHeaders := strings.Join(append(connectcors.ExposedHeaders(), "X-Request-Id", "Retry-After"), ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !originAllowed(cfg.AllowedOrigins, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		header.Set("Access-Control-Allow-Origin", origin)
+
+		// Preflight requests are answered here and never reach the service
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", allowedMethods)
+			header.Set("Access-Control-Allow-Headers", allowedHeaders)
+			header.Set("Access-Control-Max-Age", maxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", exposedHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, origin)
+}
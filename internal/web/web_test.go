@@ -0,0 +1,57 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	var reached bool
+	handler := CORS(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, MaxAge: time.Hour}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+
+	// Preflight from an allowed origin
+	req := httptest.NewRequest(http.MethodOptions, "/explore.ExploreService/PutDecision", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.False(t, reached)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Connect-Protocol-Version")
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	assert.Equal(t, "3600", rec.Header().Get("Access-Control-Max-Age"))
+
+	// Actual request from an allowed origin
+	req = httptest.NewRequest(http.MethodPost, "/explore.ExploreService/PutDecision", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.True(t, reached)
+	assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "Grpc-Status")
+
+	// Other origins get no CORS headers, so browsers block the response
+	req = httptest.NewRequest(http.MethodOptions, "/explore.ExploreService/PutDecision", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	handler := CORS(CORSConfig{AllowedOrigins: []string{"*"}}, http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "https://any.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -19,20 +19,39 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ExploreService_ListLikedYou_FullMethodName    = "/explore.ExploreService/ListLikedYou"
-	ExploreService_ListNewLikedYou_FullMethodName = "/explore.ExploreService/ListNewLikedYou"
-	ExploreService_CountLikedYou_FullMethodName   = "/explore.ExploreService/CountLikedYou"
-	ExploreService_PutDecision_FullMethodName     = "/explore.ExploreService/PutDecision"
+	ExploreService_ListLikedYou_FullMethodName        = "/explore.ExploreService/ListLikedYou"
+	ExploreService_ListNewLikedYou_FullMethodName     = "/explore.ExploreService/ListNewLikedYou"
+	ExploreService_CountLikedYou_FullMethodName       = "/explore.ExploreService/CountLikedYou"
+	ExploreService_PutDecision_FullMethodName         = "/explore.ExploreService/PutDecision"
+	ExploreService_GetQuota_FullMethodName            = "/explore.ExploreService/GetQuota"
+	ExploreService_GetExploreDeck_FullMethodName      = "/explore.ExploreService/GetExploreDeck"
+	ExploreService_GetUserScore_FullMethodName        = "/explore.ExploreService/GetUserScore"
+	ExploreService_MarkLikesSeen_FullMethodName       = "/explore.ExploreService/MarkLikesSeen"
+	ExploreService_CountUnseenLikedYou_FullMethodName = "/explore.ExploreService/CountUnseenLikedYou"
 )
 
 // ExploreServiceClient is the client API for ExploreService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ExploreServiceClient interface {
+	// List all users who liked the recipient
 	ListLikedYou(ctx context.Context, in *ListLikedYouRequest, opts ...grpc.CallOption) (*ListLikedYouResponse, error)
+	// List all users who liked the recipient excluding those who have been liked in return
 	ListNewLikedYou(ctx context.Context, in *ListLikedYouRequest, opts ...grpc.CallOption) (*ListLikedYouResponse, error)
+	// Count the number of users who liked the recipient
 	CountLikedYou(ctx context.Context, in *CountLikedYouRequest, opts ...grpc.CallOption) (*CountLikedYouResponse, error)
+	// Record the decision of the actor to like or pass the recipient
 	PutDecision(ctx context.Context, in *PutDecisionRequest, opts ...grpc.CallOption) (*PutDecisionResponse, error)
+	// Get how many likes the user has left in the current quota window
+	GetQuota(ctx context.Context, in *GetQuotaRequest, opts ...grpc.CallOption) (*GetQuotaResponse, error)
+	// Get the next candidates the actor hasn't decided on yet
+	GetExploreDeck(ctx context.Context, in *GetExploreDeckRequest, opts ...grpc.CallOption) (*GetExploreDeckResponse, error)
+	// Get a user's desirability score, internal callers only
+	GetUserScore(ctx context.Context, in *GetUserScoreRequest, opts ...grpc.CallOption) (*GetUserScoreResponse, error)
+	// Mark the recipient's likes up to a point in time as seen
+	MarkLikesSeen(ctx context.Context, in *MarkLikesSeenRequest, opts ...grpc.CallOption) (*MarkLikesSeenResponse, error)
+	// Count the likes the recipient hasn't seen yet
+	CountUnseenLikedYou(ctx context.Context, in *CountLikedYouRequest, opts ...grpc.CallOption) (*CountLikedYouResponse, error)
 }
 
 type exploreServiceClient struct {
@@ -83,14 +102,78 @@ func (c *exploreServiceClient) PutDecision(ctx context.Context, in *PutDecisionR
 	return out, nil
 }
 
+func (c *exploreServiceClient) GetQuota(ctx context.Context, in *GetQuotaRequest, opts ...grpc.CallOption) (*GetQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQuotaResponse)
+	err := c.cc.Invoke(ctx, ExploreService_GetQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exploreServiceClient) GetExploreDeck(ctx context.Context, in *GetExploreDeckRequest, opts ...grpc.CallOption) (*GetExploreDeckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetExploreDeckResponse)
+	err := c.cc.Invoke(ctx, ExploreService_GetExploreDeck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exploreServiceClient) GetUserScore(ctx context.Context, in *GetUserScoreRequest, opts ...grpc.CallOption) (*GetUserScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserScoreResponse)
+	err := c.cc.Invoke(ctx, ExploreService_GetUserScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exploreServiceClient) MarkLikesSeen(ctx context.Context, in *MarkLikesSeenRequest, opts ...grpc.CallOption) (*MarkLikesSeenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkLikesSeenResponse)
+	err := c.cc.Invoke(ctx, ExploreService_MarkLikesSeen_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exploreServiceClient) CountUnseenLikedYou(ctx context.Context, in *CountLikedYouRequest, opts ...grpc.CallOption) (*CountLikedYouResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountLikedYouResponse)
+	err := c.cc.Invoke(ctx, ExploreService_CountUnseenLikedYou_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExploreServiceServer is the server API for ExploreService service.
 // All implementations must embed UnimplementedExploreServiceServer
 // for forward compatibility.
 type ExploreServiceServer interface {
+	// List all users who liked the recipient
 	ListLikedYou(context.Context, *ListLikedYouRequest) (*ListLikedYouResponse, error)
+	// List all users who liked the recipient excluding those who have been liked in return
 	ListNewLikedYou(context.Context, *ListLikedYouRequest) (*ListLikedYouResponse, error)
+	// Count the number of users who liked the recipient
 	CountLikedYou(context.Context, *CountLikedYouRequest) (*CountLikedYouResponse, error)
+	// Record the decision of the actor to like or pass the recipient
 	PutDecision(context.Context, *PutDecisionRequest) (*PutDecisionResponse, error)
+	// Get how many likes the user has left in the current quota window
+	GetQuota(context.Context, *GetQuotaRequest) (*GetQuotaResponse, error)
+	// Get the next candidates the actor hasn't decided on yet
+	GetExploreDeck(context.Context, *GetExploreDeckRequest) (*GetExploreDeckResponse, error)
+	// Get a user's desirability score, internal callers only
+	GetUserScore(context.Context, *GetUserScoreRequest) (*GetUserScoreResponse, error)
+	// Mark the recipient's likes up to a point in time as seen
+	MarkLikesSeen(context.Context, *MarkLikesSeenRequest) (*MarkLikesSeenResponse, error)
+	// Count the likes the recipient hasn't seen yet
+	CountUnseenLikedYou(context.Context, *CountLikedYouRequest) (*CountLikedYouResponse, error)
 	mustEmbedUnimplementedExploreServiceServer()
 }
 
@@ -113,6 +196,21 @@ func (UnimplementedExploreServiceServer) CountLikedYou(context.Context, *CountLi
 func (UnimplementedExploreServiceServer) PutDecision(context.Context, *PutDecisionRequest) (*PutDecisionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutDecision not implemented")
 }
+func (UnimplementedExploreServiceServer) GetQuota(context.Context, *GetQuotaRequest) (*GetQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuota not implemented")
+}
+func (UnimplementedExploreServiceServer) GetExploreDeck(context.Context, *GetExploreDeckRequest) (*GetExploreDeckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExploreDeck not implemented")
+}
+func (UnimplementedExploreServiceServer) GetUserScore(context.Context, *GetUserScoreRequest) (*GetUserScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserScore not implemented")
+}
+func (UnimplementedExploreServiceServer) MarkLikesSeen(context.Context, *MarkLikesSeenRequest) (*MarkLikesSeenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkLikesSeen not implemented")
+}
+func (UnimplementedExploreServiceServer) CountUnseenLikedYou(context.Context, *CountLikedYouRequest) (*CountLikedYouResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountUnseenLikedYou not implemented")
+}
 func (UnimplementedExploreServiceServer) mustEmbedUnimplementedExploreServiceServer() {}
 func (UnimplementedExploreServiceServer) testEmbeddedByValue()                        {}
 
@@ -206,6 +304,96 @@ func _ExploreService_PutDecision_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ExploreService_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExploreServiceServer).GetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExploreService_GetQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExploreServiceServer).GetQuota(ctx, req.(*GetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExploreService_GetExploreDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExploreDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExploreServiceServer).GetExploreDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExploreService_GetExploreDeck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExploreServiceServer).GetExploreDeck(ctx, req.(*GetExploreDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExploreService_GetUserScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExploreServiceServer).GetUserScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExploreService_GetUserScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExploreServiceServer).GetUserScore(ctx, req.(*GetUserScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExploreService_MarkLikesSeen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkLikesSeenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExploreServiceServer).MarkLikesSeen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExploreService_MarkLikesSeen_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExploreServiceServer).MarkLikesSeen(ctx, req.(*MarkLikesSeenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExploreService_CountUnseenLikedYou_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountLikedYouRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExploreServiceServer).CountUnseenLikedYou(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExploreService_CountUnseenLikedYou_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExploreServiceServer).CountUnseenLikedYou(ctx, req.(*CountLikedYouRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ExploreService_ServiceDesc is the grpc.ServiceDesc for ExploreService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +417,26 @@ var ExploreService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PutDecision",
 			Handler:    _ExploreService_PutDecision_Handler,
 		},
+		{
+			MethodName: "GetQuota",
+			Handler:    _ExploreService_GetQuota_Handler,
+		},
+		{
+			MethodName: "GetExploreDeck",
+			Handler:    _ExploreService_GetExploreDeck_Handler,
+		},
+		{
+			MethodName: "GetUserScore",
+			Handler:    _ExploreService_GetUserScore_Handler,
+		},
+		{
+			MethodName: "MarkLikesSeen",
+			Handler:    _ExploreService_MarkLikesSeen_Handler,
+		},
+		{
+			MethodName: "CountUnseenLikedYou",
+			Handler:    _ExploreService_CountUnseenLikedYou_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/explore-service.proto",